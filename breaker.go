@@ -0,0 +1,310 @@
+package falkordb
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/snowmerak/falkordb-go/graph"
+)
+
+// ErrCircuitOpen is returned without contacting the server while the
+// circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// ErrTooManyInFlight is returned without contacting the server when a graph
+// already has the maximum number of queries in flight.
+var ErrTooManyInFlight = errors.New("too many queries in flight")
+
+const (
+	defaultBreakerWindow       = 10 * time.Second
+	defaultBreakerMinRequests  = 20
+	defaultBreakerFailureRatio = 0.5
+	defaultBreakerOpenTimeout  = 5 * time.Second
+)
+
+// Clock tells the time to the circuit breaker.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// BreakerState is the state of a circuit breaker.
+type BreakerState int
+
+const (
+	// BreakerClosed lets queries through.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen fails queries with ErrCircuitOpen.
+	BreakerOpen
+	// BreakerHalfOpen is probing whether the server recovered.
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// BreakerOptions configures the circuit breaker and concurrency limiter.
+// Zero values select the defaults.
+type BreakerOptions struct {
+	// PerGraph keeps a breaker per graph instead of one for the client.
+	PerGraph bool
+	// Window is the period over which the failure ratio is measured
+	// (default 10s).
+	Window time.Duration
+	// MinRequests is the number of queries in a window below which the
+	// breaker does not trip (default 20).
+	MinRequests int
+	// FailureRatio is the ratio of failed queries in a window that trips the
+	// breaker (default 0.5).
+	FailureRatio float64
+	// OpenTimeout is how long the breaker stays open before a GRAPH.LIST
+	// probe checks whether the server recovered (default 5s).
+	OpenTimeout time.Duration
+	// MaxInFlight is the maximum number of concurrent queries per graph;
+	// zero means no limit.
+	MaxInFlight int
+	// IsFailure reports whether an error counts as a failure. Nil counts
+	// timeouts and rejections for too many pending queries, which are the
+	// errors of an overloaded server. Queries whose context is done when
+	// they return are not counted at all.
+	IsFailure func(err error) bool
+	// Clock is used for the window and the open timeout; nil uses the
+	// system clock.
+	Clock Clock
+}
+
+// IsOverloaded reports whether err shows that the server is overloaded: a
+// query or network timeout, or a rejection for too many pending queries. The
+// deadline of the caller's context expiring is not a server failure.
+func IsOverloaded(err error) bool {
+	if err == nil || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var nerr net.Error
+	if errors.As(err, &nerr) && nerr.Timeout() {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "query timed out") || strings.Contains(msg, "max pending queries exceeded")
+}
+
+// breakerCounts is the state of one breaker.
+type breakerCounts struct {
+	state       BreakerState
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+}
+
+type breaker struct {
+	opts  BreakerOptions
+	probe func(ctx context.Context) error
+
+	mu       sync.Mutex
+	circuits map[string]*breakerCounts
+	inFlight map[string]int
+}
+
+// SetBreaker puts a circuit breaker and concurrency limiter in front of the
+// queries of graphs selected afterwards. It runs outside the hooks and the
+// retry policy of the graph, so retries of a query count once. Passing nil
+// removes it.
+func (db *FalkorDB) SetBreaker(opts *BreakerOptions) {
+	if opts == nil {
+		db.breaker = nil
+		return
+	}
+
+	db.breaker = newBreaker(*opts, func(ctx context.Context) error {
+		return db.Conn.Do(ctx, "GRAPH.LIST").Err()
+	})
+}
+
+// newBreaker creates a breaker with the defaults applied to o. probe checks
+// whether the server recovered.
+func newBreaker(o BreakerOptions, probe func(ctx context.Context) error) *breaker {
+	if o.Window <= 0 {
+		o.Window = defaultBreakerWindow
+	}
+	if o.MinRequests <= 0 {
+		o.MinRequests = defaultBreakerMinRequests
+	}
+	if o.FailureRatio <= 0 {
+		o.FailureRatio = defaultBreakerFailureRatio
+	}
+	if o.OpenTimeout <= 0 {
+		o.OpenTimeout = defaultBreakerOpenTimeout
+	}
+	if o.IsFailure == nil {
+		o.IsFailure = IsOverloaded
+	}
+	if o.Clock == nil {
+		o.Clock = systemClock{}
+	}
+	return &breaker{
+		opts:     o,
+		probe:    probe,
+		circuits: make(map[string]*breakerCounts),
+		inFlight: make(map[string]int),
+	}
+}
+
+// BreakerState returns the state of the breaker guarding graphName.
+func (db *FalkorDB) BreakerState(graphName string) BreakerState {
+	b := db.breaker
+	if b == nil {
+		return BreakerClosed
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c := b.circuits[b.key(graphName)]
+	if c == nil {
+		return BreakerClosed
+	}
+	return c.state
+}
+
+func (b *breaker) key(graphName string) string {
+	if b.opts.PerGraph {
+		return graphName
+	}
+	return ""
+}
+
+// hook is the graph.QueryHook enforcing the breaker.
+func (b *breaker) hook(next graph.QueryFunc) graph.QueryFunc {
+	return func(ctx context.Context, g *graph.Graph, req graph.QueryRequest) (*graph.QueryResult, error) {
+		probe, err := b.admit(g.Id)
+		if err != nil {
+			return nil, err
+		}
+		if probe {
+			if err := b.probe(ctx); err != nil {
+				b.probed(g.Id, false)
+				return nil, ErrCircuitOpen
+			}
+			b.probed(g.Id, true)
+		}
+
+		res, err := next(ctx, g, req)
+		if ctx.Err() != nil {
+			// the caller gave up, which says nothing about the server
+			b.abandoned(g.Id)
+			return res, err
+		}
+		b.done(g.Id, err)
+		return res, err
+	}
+}
+
+// admit decides whether a query may run and whether it has to probe first.
+func (b *breaker) admit(graphName string) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(graphName)
+	probe := false
+	switch c.state {
+	case BreakerOpen:
+		if b.opts.Clock.Now().Sub(c.openedAt) < b.opts.OpenTimeout {
+			return false, ErrCircuitOpen
+		}
+		c.state = BreakerHalfOpen
+		probe = true
+	case BreakerHalfOpen:
+		return false, ErrCircuitOpen
+	}
+
+	if b.opts.MaxInFlight > 0 && b.inFlight[graphName] >= b.opts.MaxInFlight {
+		if probe {
+			// wait a full open timeout again rather than probing on every
+			// query until a slot frees up
+			c.state = BreakerOpen
+			c.openedAt = b.opts.Clock.Now()
+		}
+		return false, ErrTooManyInFlight
+	}
+	b.inFlight[graphName]++
+	return probe, nil
+}
+
+func (b *breaker) circuit(graphName string) *breakerCounts {
+	key := b.key(graphName)
+	c := b.circuits[key]
+	if c == nil {
+		c = &breakerCounts{windowStart: b.opts.Clock.Now()}
+		b.circuits[key] = c
+	}
+	return c
+}
+
+// probed records the outcome of a half-open probe.
+func (b *breaker) probed(graphName string, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(graphName)
+	now := b.opts.Clock.Now()
+	if ok {
+		*c = breakerCounts{state: BreakerClosed, windowStart: now}
+		return
+	}
+	c.state = BreakerOpen
+	c.openedAt = now
+	b.release(graphName)
+}
+
+// done records the outcome of a query.
+func (b *breaker) done(graphName string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.release(graphName)
+
+	c := b.circuit(graphName)
+	now := b.opts.Clock.Now()
+	if now.Sub(c.windowStart) >= b.opts.Window {
+		c.windowStart, c.requests, c.failures = now, 0, 0
+	}
+	c.requests++
+	if err != nil && b.opts.IsFailure(err) {
+		c.failures++
+	}
+	if c.state == BreakerClosed && c.requests >= b.opts.MinRequests &&
+		float64(c.failures) >= b.opts.FailureRatio*float64(c.requests) {
+		c.state = BreakerOpen
+		c.openedAt = now
+	}
+}
+
+// abandoned releases a query without recording its outcome.
+func (b *breaker) abandoned(graphName string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.release(graphName)
+}
+
+func (b *breaker) release(graphName string) {
+	if b.inFlight[graphName] <= 1 {
+		delete(b.inFlight, graphName)
+	} else {
+		b.inFlight[graphName]--
+	}
+}
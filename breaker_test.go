@@ -0,0 +1,177 @@
+package falkordb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/snowmerak/falkordb-go/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time { return c.now }
+
+var errOverloaded = errors.New("Query timed out")
+
+func TestBreakerTripsAndRecovers(t *testing.T) {
+	clock := &testClock{now: time.Unix(0, 0)}
+	probeErr := errors.New("still down")
+	probes := 0
+	b := newBreaker(BreakerOptions{
+		Window:       10 * time.Second,
+		MinRequests:  4,
+		FailureRatio: 0.5,
+		OpenTimeout:  5 * time.Second,
+		Clock:        clock,
+	}, func(ctx context.Context) error {
+		probes++
+		return probeErr
+	})
+
+	nextErr := errOverloaded
+	calls := 0
+	run := b.hook(func(ctx context.Context, g *graph.Graph, req graph.QueryRequest) (*graph.QueryResult, error) {
+		calls++
+		return nil, nextErr
+	})
+	g := graph.New("g", nil)
+	query := func() error {
+		_, err := run(context.Background(), g, graph.QueryRequest{Command: graph.CmdROQuery, Query: "RETURN 1"})
+		return err
+	}
+
+	// below MinRequests the breaker stays closed
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, errOverloaded, query())
+		assert.Equal(t, BreakerClosed, b.circuits[""].state)
+	}
+	assert.Equal(t, errOverloaded, query())
+	assert.Equal(t, BreakerOpen, b.circuits[""].state)
+
+	// open: rejected without calling next
+	assert.Equal(t, ErrCircuitOpen, query())
+	assert.Equal(t, 4, calls)
+
+	// after the open timeout a failing probe reopens the circuit
+	clock.now = clock.now.Add(5 * time.Second)
+	assert.Equal(t, ErrCircuitOpen, query())
+	assert.Equal(t, 1, probes)
+	assert.Equal(t, 4, calls)
+	assert.Equal(t, BreakerOpen, b.circuits[""].state)
+	assert.Empty(t, b.inFlight)
+
+	// a successful probe closes it and lets the query through
+	clock.now = clock.now.Add(5 * time.Second)
+	probeErr, nextErr = nil, nil
+	assert.NoError(t, query())
+	assert.Equal(t, 2, probes)
+	assert.Equal(t, 5, calls)
+	assert.Equal(t, BreakerClosed, b.circuits[""].state)
+	assert.Equal(t, 1, b.circuits[""].requests)
+}
+
+func TestBreakerWindowAndFailureClassification(t *testing.T) {
+	clock := &testClock{now: time.Unix(0, 0)}
+	b := newBreaker(BreakerOptions{MinRequests: 2, Window: time.Second, Clock: clock}, nil)
+
+	// errors that are not failures do not count
+	b.inFlight["g"] = 2
+	b.done("g", errors.New("errMsg: Invalid input"))
+	b.done("g", errors.New("errMsg: Invalid input"))
+	assert.Equal(t, BreakerClosed, b.circuits[""].state)
+	assert.Equal(t, 0, b.circuits[""].failures)
+
+	// a new window forgets earlier requests
+	clock.now = clock.now.Add(time.Second)
+	b.inFlight["g"] = 1
+	b.done("g", errOverloaded)
+	assert.Equal(t, 1, b.circuits[""].requests)
+	assert.Equal(t, BreakerClosed, b.circuits[""].state)
+}
+
+func TestBreakerPerGraphAndMaxInFlight(t *testing.T) {
+	clock := &testClock{now: time.Unix(0, 0)}
+	b := newBreaker(BreakerOptions{PerGraph: true, MinRequests: 1, MaxInFlight: 2, Clock: clock}, nil)
+
+	_, err := b.admit("a")
+	require.NoError(t, err)
+	_, err = b.admit("a")
+	require.NoError(t, err)
+	_, err = b.admit("a")
+	assert.Equal(t, ErrTooManyInFlight, err)
+	_, err = b.admit("b")
+	assert.NoError(t, err, "the limit is per graph")
+
+	b.done("a", errOverloaded)
+	assert.Equal(t, BreakerOpen, b.circuits["a"].state)
+	assert.Equal(t, BreakerClosed, b.circuits["b"].state)
+	assert.Equal(t, 1, b.inFlight["a"])
+
+	_, err = b.admit("a")
+	assert.Equal(t, ErrCircuitOpen, err)
+	_, err = b.admit("b")
+	assert.NoError(t, err)
+
+	// a probe that cannot get an in-flight slot keeps the circuit open for
+	// another open timeout
+	clock.now = clock.now.Add(defaultBreakerOpenTimeout)
+	b.inFlight["a"] = 2
+	_, err = b.admit("a")
+	assert.Equal(t, ErrTooManyInFlight, err)
+	assert.Equal(t, BreakerOpen, b.circuits["a"].state)
+	assert.Equal(t, clock.now, b.circuits["a"].openedAt)
+
+	b.inFlight["a"] = 0
+	_, err = b.admit("a")
+	assert.Equal(t, ErrCircuitOpen, err)
+
+	clock.now = clock.now.Add(defaultBreakerOpenTimeout)
+	probe, err := b.admit("a")
+	require.NoError(t, err)
+	assert.True(t, probe)
+	assert.Equal(t, BreakerHalfOpen, b.circuits["a"].state)
+	_, err = b.admit("a")
+	assert.Equal(t, ErrCircuitOpen, err, "only one probe at a time")
+
+	b.probed("a", true)
+	assert.Equal(t, BreakerClosed, b.circuits["a"].state)
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestIsOverloaded(t *testing.T) {
+	assert.False(t, IsOverloaded(nil))
+	assert.True(t, IsOverloaded(errOverloaded))
+	assert.True(t, IsOverloaded(errors.New("Max pending queries exceeded")))
+	assert.True(t, IsOverloaded(timeoutError{}))
+	assert.False(t, IsOverloaded(context.DeadlineExceeded))
+	assert.False(t, IsOverloaded(fmt.Errorf("query: %w", context.DeadlineExceeded)))
+	assert.False(t, IsOverloaded(context.Canceled))
+}
+
+func TestBreakerIgnoresCallerDeadlines(t *testing.T) {
+	b := newBreaker(BreakerOptions{MinRequests: 1, IsFailure: func(error) bool { return true }}, nil)
+	run := b.hook(func(ctx context.Context, g *graph.Graph, req graph.QueryRequest) (*graph.QueryResult, error) {
+		<-ctx.Done()
+		return nil, timeoutError{}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	_, err := run(ctx, graph.New("g", nil), graph.QueryRequest{Query: "RETURN 1"})
+	assert.Equal(t, timeoutError{}, err)
+	assert.Equal(t, BreakerClosed, b.circuits[""].state)
+	assert.Equal(t, 0, b.circuits[""].requests)
+	assert.Empty(t, b.inFlight)
+}
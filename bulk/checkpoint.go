@@ -0,0 +1,141 @@
+package bulk
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// checkpoint records how far a load has progressed. The node ID mapping is
+// kept in an append-only file next to it, whose committed length is IDsSize.
+type checkpoint struct {
+	Graph     string   `json:"graph"`
+	Files     []string `json:"files"`
+	File      int      `json:"file"`
+	Rows      int      `json:"rows"`
+	IDsSize   int64    `json:"ids_size"`
+	Nodes     int      `json:"nodes"`
+	Relations int      `json:"relations"`
+	Rejected  int      `json:"rejected"`
+}
+
+func readCheckpoint(path string) (*checkpoint, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cp := new(checkpoint)
+	if err := json.Unmarshal(b, cp); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint %s: %w", path, err)
+	}
+	return cp, nil
+}
+
+// write replaces the checkpoint file atomically.
+func (cp *checkpoint) write(path string) error {
+	b, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// idKey identifies a node by namespace and CSV ID.
+type idKey struct {
+	namespace string
+	id        string
+}
+
+// idLog appends node ID mappings to the checkpoint's ids file.
+type idLog struct {
+	f    *os.File
+	w    *bufio.Writer
+	size int64
+}
+
+// openIDLog opens the ids file, truncating it to the committed size and
+// loading the mappings it contains into ids.
+func openIDLog(path string, size int64, ids map[idKey]int64) (*idLog, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Truncate(size); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	rd := bufio.NewReader(io.LimitReader(f, size))
+	for line := 1; ; line++ {
+		text, err := rd.ReadString('\n')
+		if err == io.EOF && text == "" {
+			break
+		}
+		if err != nil && err != io.EOF {
+			f.Close()
+			return nil, err
+		}
+		key, id, perr := parseIDLine(strings.TrimSuffix(text, "\n"))
+		if perr != nil {
+			f.Close()
+			return nil, fmt.Errorf("ids file line %d: %w", line, perr)
+		}
+		ids[key] = id
+	}
+
+	if _, err := f.Seek(size, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &idLog{f: f, w: bufio.NewWriter(f), size: size}, nil
+}
+
+func (l *idLog) append(key idKey, id int64) error {
+	line := strconv.Quote(key.namespace) + " " + strconv.Quote(key.id) + " " + strconv.FormatInt(id, 10) + "\n"
+	n, err := l.w.WriteString(line)
+	l.size += int64(n)
+	return err
+}
+
+// commit flushes the appended mappings and returns the committed size.
+func (l *idLog) commit() (int64, error) {
+	if err := l.w.Flush(); err != nil {
+		return 0, err
+	}
+	return l.size, l.f.Sync()
+}
+
+func (l *idLog) Close() error {
+	return l.f.Close()
+}
+
+func parseIDLine(line string) (idKey, int64, error) {
+	ns, err := strconv.QuotedPrefix(line)
+	if err != nil {
+		return idKey{}, 0, err
+	}
+	rest := strings.TrimPrefix(line[len(ns):], " ")
+	id, err := strconv.QuotedPrefix(rest)
+	if err != nil {
+		return idKey{}, 0, err
+	}
+	internal, err := strconv.ParseInt(strings.TrimPrefix(rest[len(id):], " "), 10, 64)
+	if err != nil {
+		return idKey{}, 0, err
+	}
+	ns, _ = strconv.Unquote(ns)
+	id, _ = strconv.Unquote(id)
+	return idKey{namespace: ns, id: id}, internal, nil
+}
@@ -0,0 +1,54 @@
+package bulk
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIDLogResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "load.ids")
+
+	log, err := openIDLog(path, 0, map[idKey]int64{})
+	require.NoError(t, err)
+	require.NoError(t, log.append(idKey{namespace: "User", id: "a \"quoted\" id"}, 1))
+	require.NoError(t, log.append(idKey{namespace: "", id: "b"}, 2))
+	committed, err := log.commit()
+	require.NoError(t, err)
+
+	// a write after the last commit is discarded on resume
+	require.NoError(t, log.append(idKey{namespace: "User", id: "c"}, 3))
+	_, err = log.commit()
+	require.NoError(t, err)
+	require.NoError(t, log.Close())
+
+	ids := map[idKey]int64{}
+	log, err = openIDLog(path, committed, ids)
+	require.NoError(t, err)
+	defer log.Close()
+	assert.Equal(t, map[idKey]int64{
+		{namespace: "User", id: "a \"quoted\" id"}: 1,
+		{namespace: "", id: "b"}:                   2,
+	}, ids)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, committed, info.Size())
+}
+
+func TestCheckpointRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "load.json")
+
+	cp, err := readCheckpoint(path)
+	require.NoError(t, err)
+	assert.Nil(t, cp)
+
+	want := &checkpoint{Graph: "g", Files: []string{"node:a.csv"}, File: 1, Rows: 20, IDsSize: 64, Nodes: 19, Rejected: 1}
+	require.NoError(t, want.write(path))
+	cp, err = readCheckpoint(path)
+	require.NoError(t, err)
+	assert.Equal(t, want, cp)
+}
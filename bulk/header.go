@@ -0,0 +1,301 @@
+package bulk
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ColumnType is the declared type of a CSV column.
+type ColumnType int
+
+const (
+	// TypeInfer infers the type of every value of an untyped column.
+	TypeInfer ColumnType = iota
+	// TypeID marks the column identifying a node within its ID namespace.
+	TypeID
+	// TypeStartID marks the column holding the ID of a relationship's source node.
+	TypeStartID
+	// TypeEndID marks the column holding the ID of a relationship's destination node.
+	TypeEndID
+	// TypeIgnore skips the column.
+	TypeIgnore
+	TypeString
+	TypeInt
+	TypeFloat
+	TypeBool
+	TypeArray
+)
+
+var columnTypeNames = map[string]ColumnType{
+	"ID":       TypeID,
+	"START_ID": TypeStartID,
+	"END_ID":   TypeEndID,
+	"IGNORE":   TypeIgnore,
+	"STRING":   TypeString,
+	"INT":      TypeInt,
+	"INTEGER":  TypeInt,
+	"LONG":     TypeInt,
+	"FLOAT":    TypeFloat,
+	"DOUBLE":   TypeFloat,
+	"BOOL":     TypeBool,
+	"BOOLEAN":  TypeBool,
+	"ARRAY":    TypeArray,
+}
+
+// Column describes a column of a CSV header such as "name:STRING",
+// ":ID(User)" or "age".
+type Column struct {
+	// Name is the property name. ID columns without a name are not stored.
+	Name string
+	Type ColumnType
+	// Namespace is the ID namespace of ID, START_ID and END_ID columns.
+	Namespace string
+}
+
+// ParseHeader parses the header row of a CSV file. A field whose suffix after
+// the last colon is not a known type is treated as an untyped property name.
+// Property names may hold any character, such as spaces or backticks; they
+// are quoted when the rows are sent.
+func ParseHeader(fields []string) ([]Column, error) {
+	columns := make([]Column, len(fields))
+	for i, field := range fields {
+		c, err := parseColumn(strings.TrimSpace(field))
+		if err != nil {
+			return nil, fmt.Errorf("column %d: %w", i, err)
+		}
+		columns[i] = c
+	}
+	return columns, nil
+}
+
+func parseColumn(field string) (Column, error) {
+	idx := strings.LastIndexByte(field, ':')
+	if idx < 0 {
+		if field == "" {
+			return Column{}, errors.New("property name is required")
+		}
+		return Column{Name: field, Type: TypeInfer}, nil
+	}
+
+	name, spec := field[:idx], field[idx+1:]
+	namespace := ""
+	if open := strings.IndexByte(spec, '('); open >= 0 {
+		if !strings.HasSuffix(spec, ")") {
+			return Column{}, fmt.Errorf("invalid column %q", field)
+		}
+		namespace = spec[open+1 : len(spec)-1]
+		spec = spec[:open]
+	}
+
+	t, ok := columnTypeNames[strings.ToUpper(spec)]
+	if !ok {
+		return Column{Name: field, Type: TypeInfer}, nil
+	}
+	if namespace != "" && t != TypeID && t != TypeStartID && t != TypeEndID {
+		return Column{}, fmt.Errorf("column %q: only ID columns take a namespace", field)
+	}
+	if name == "" && t != TypeID && t != TypeStartID && t != TypeEndID && t != TypeIgnore {
+		return Column{}, fmt.Errorf("column %q: property name is required", field)
+	}
+	return Column{Name: name, Type: t, Namespace: namespace}, nil
+}
+
+// header is a validated node or relationship header.
+type header struct {
+	columns []Column
+	id      int
+	start   int
+	end     int
+}
+
+func nodeHeader(columns []Column) (*header, error) {
+	h := &header{columns: columns, id: -1, start: -1, end: -1}
+	for i, c := range columns {
+		switch c.Type {
+		case TypeID:
+			if h.id >= 0 {
+				return nil, errors.New("node header has more than one ID column")
+			}
+			h.id = i
+		case TypeStartID, TypeEndID:
+			return nil, errors.New("node header must not contain START_ID or END_ID columns")
+		}
+	}
+	return h, nil
+}
+
+func relationHeader(columns []Column) (*header, error) {
+	h := &header{columns: columns, id: -1, start: -1, end: -1}
+	for i, c := range columns {
+		switch c.Type {
+		case TypeID:
+			return nil, errors.New("relationship header must not contain an ID column")
+		case TypeStartID:
+			if h.start >= 0 {
+				return nil, errors.New("relationship header has more than one START_ID column")
+			}
+			h.start = i
+		case TypeEndID:
+			if h.end >= 0 {
+				return nil, errors.New("relationship header has more than one END_ID column")
+			}
+			h.end = i
+		}
+	}
+	if h.start < 0 || h.end < 0 {
+		return nil, errors.New("relationship header requires START_ID and END_ID columns")
+	}
+	return h, nil
+}
+
+// properties converts the property columns of a record. Empty values are
+// treated as missing.
+func (h *header) properties(record []string) (map[string]interface{}, error) {
+	if len(record) != len(h.columns) {
+		return nil, fmt.Errorf("expected %d fields, got %d", len(h.columns), len(record))
+	}
+	props := make(map[string]interface{}, len(record))
+	for i, c := range h.columns {
+		switch c.Type {
+		case TypeIgnore, TypeStartID, TypeEndID:
+			continue
+		case TypeID:
+			if c.Name != "" && record[i] != "" {
+				props[c.Name] = record[i]
+			}
+			continue
+		}
+		v, err := c.Convert(record[i])
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", c.Name, err)
+		}
+		if v != nil {
+			props[c.Name] = v
+		}
+	}
+	return props, nil
+}
+
+// Convert converts a raw field to the column's type. Empty fields convert
+// to nil.
+func (c Column) Convert(s string) (interface{}, error) {
+	if s == "" {
+		return nil, nil
+	}
+	switch c.Type {
+	case TypeString, TypeID, TypeStartID, TypeEndID:
+		return s, nil
+	case TypeInt:
+		return strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	case TypeFloat:
+		return strconv.ParseFloat(strings.TrimSpace(s), 64)
+	case TypeBool:
+		return strconv.ParseBool(strings.ToLower(strings.TrimSpace(s)))
+	case TypeArray:
+		return parseArray(strings.TrimSpace(s))
+	case TypeIgnore:
+		return nil, nil
+	default:
+		return inferValue(s), nil
+	}
+}
+
+// inferValue converts a field of an untyped column to a bool, integer,
+// float or array when it parses as one and keeps it as a string otherwise.
+func inferValue(s string) interface{} {
+	t := strings.TrimSpace(s)
+	switch strings.ToLower(t) {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if i, err := strconv.ParseInt(t, 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(t, 64); err == nil {
+		return f
+	}
+	if strings.HasPrefix(t, "[") && strings.HasSuffix(t, "]") {
+		if arr, err := parseArray(t); err == nil {
+			return arr
+		}
+	}
+	return s
+}
+
+// parseArray parses an array literal such as [1, 2.5, 'a', ["b", true]].
+// Quoted elements are strings; other elements are inferred.
+func parseArray(s string) ([]interface{}, error) {
+	if len(s) < 2 || s[0] != '[' || s[len(s)-1] != ']' {
+		return nil, fmt.Errorf("invalid array %q", s)
+	}
+	body := strings.TrimSpace(s[1 : len(s)-1])
+	arr := []interface{}{}
+	if body == "" {
+		return arr, nil
+	}
+
+	depth, start := 0, 0
+	var quote byte
+	for i := 0; i <= len(body); i++ {
+		if i < len(body) {
+			c := body[i]
+			switch {
+			case quote != 0:
+				if c == '\\' {
+					i++
+				} else if c == quote {
+					quote = 0
+				}
+				continue
+			case c == '\'' || c == '"':
+				quote = c
+				continue
+			case c == '[':
+				depth++
+				continue
+			case c == ']':
+				depth--
+				continue
+			case c != ',' || depth > 0:
+				continue
+			}
+		}
+		if quote != 0 || depth != 0 {
+			return nil, fmt.Errorf("invalid array %q", s)
+		}
+
+		elem, err := parseArrayElement(strings.TrimSpace(body[start:i]))
+		if err != nil {
+			return nil, err
+		}
+		arr = append(arr, elem)
+		start = i + 1
+	}
+	return arr, nil
+}
+
+func parseArrayElement(s string) (interface{}, error) {
+	switch {
+	case s == "":
+		return nil, errors.New("empty array element")
+	case s[0] == '[':
+		return parseArray(s)
+	case s[0] == '\'' || s[0] == '"':
+		if len(s) < 2 || s[len(s)-1] != s[0] {
+			return nil, fmt.Errorf("unterminated string %s", s)
+		}
+		inner := s[1 : len(s)-1]
+		if s[0] == '\'' {
+			inner = strings.ReplaceAll(strings.ReplaceAll(inner, `\'`, `'`), `"`, `\"`)
+		}
+		return strconv.Unquote(`"` + inner + `"`)
+	case strings.EqualFold(s, "null"):
+		return nil, nil
+	default:
+		return inferValue(s), nil
+	}
+}
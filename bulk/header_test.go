@@ -0,0 +1,101 @@
+package bulk
+
+import (
+	"testing"
+
+	"github.com/snowmerak/falkordb-go/util/strs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHeader(t *testing.T) {
+	columns, err := ParseHeader([]string{"uid:ID(User)", "name:STRING", "age:int", "score", ":IGNORE", "tags:ARRAY", "a:b"})
+	require.NoError(t, err)
+	assert.Equal(t, []Column{
+		{Name: "uid", Type: TypeID, Namespace: "User"},
+		{Name: "name", Type: TypeString},
+		{Name: "age", Type: TypeInt},
+		{Name: "score", Type: TypeInfer},
+		{Type: TypeIgnore},
+		{Name: "tags", Type: TypeArray},
+		{Name: "a:b", Type: TypeInfer},
+	}, columns)
+
+	_, err = ParseHeader([]string{":STRING"})
+	assert.Error(t, err)
+	_, err = ParseHeader([]string{"name", " "})
+	assert.EqualError(t, err, "column 1: property name is required")
+	_, err = ParseHeader([]string{"x:INT(ns)"})
+	assert.Error(t, err)
+	_, err = ParseHeader([]string{"x:ID(ns"})
+	assert.Error(t, err)
+}
+
+func TestHeaderValidation(t *testing.T) {
+	columns, _ := ParseHeader([]string{":ID", ":ID"})
+	_, err := nodeHeader(columns)
+	assert.Error(t, err)
+
+	columns, _ = ParseHeader([]string{":START_ID(User)", "since:INT"})
+	_, err = relationHeader(columns)
+	assert.EqualError(t, err, "relationship header requires START_ID and END_ID columns")
+
+	columns, _ = ParseHeader([]string{":START_ID(User)", ":END_ID(User)", "since:INT"})
+	h, err := relationHeader(columns)
+	require.NoError(t, err)
+	assert.Equal(t, 0, h.start)
+	assert.Equal(t, 1, h.end)
+}
+
+func TestHeaderProperties(t *testing.T) {
+	columns, _ := ParseHeader([]string{"uid:ID(User)", ":IGNORE", "age:INT", "active:BOOL", "misc"})
+	h, err := nodeHeader(columns)
+	require.NoError(t, err)
+
+	props, err := h.properties([]string{"u1", "x", "42", "TRUE", ""})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"uid": "u1", "age": int64(42), "active": true}, props)
+
+	_, err = h.properties([]string{"u1", "x", "forty", "true", ""})
+	assert.ErrorContains(t, err, "column age")
+
+	_, err = h.properties([]string{"u1"})
+	assert.EqualError(t, err, "expected 5 fields, got 1")
+}
+
+func TestInferValue(t *testing.T) {
+	assert.Equal(t, int64(7), inferValue("7"))
+	assert.Equal(t, 1.5, inferValue("1.5"))
+	assert.Equal(t, false, inferValue("False"))
+	assert.Equal(t, "hello", inferValue("hello"))
+	assert.Equal(t, []interface{}{int64(1), "a"}, inferValue("[1, 'a']"))
+	assert.Equal(t, "[1, 'a'", inferValue("[1, 'a'"))
+}
+
+func TestParseArray(t *testing.T) {
+	arr, err := parseArray(`[1, 2.5, 'it\'s', "a, b", [true, null], []]`)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{int64(1), 2.5, "it's", "a, b", []interface{}{true, nil}, []interface{}{}}, arr)
+
+	for _, bad := range []string{"1, 2", "[1,,2]", "['a]", "[[1]"} {
+		_, err := parseArray(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestHeaderQuotedPropertyNames(t *testing.T) {
+	columns, err := ParseHeader([]string{"first name:STRING", "a`b", "x}) DETACH DELETE n //"})
+	require.NoError(t, err)
+	h, err := nodeHeader(columns)
+	require.NoError(t, err)
+	props, err := h.properties([]string{"Ann", "1", "2"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"first name": "Ann", "a`b": int64(1), "x}) DETACH DELETE n //": int64(2)}, props)
+
+	// the names are sent quoted in the rows parameter
+	for name, want := range map[string]string{"first name": "{`first name`: 1}", "a`b": "{`a``b`: 1}"} {
+		encoded, err := strs.AppendValue(nil, map[string]interface{}{name: 1})
+		require.NoError(t, err)
+		assert.Equal(t, want, string(encoded))
+	}
+}
@@ -0,0 +1,449 @@
+// Package bulk loads graphs from node and relationship CSV files in the
+// format of the FalkorDB bulk-insert tool.
+//
+// Every file holds one label or relationship type, taken from the file name
+// unless given explicitly. Header fields declare a property name and type,
+// such as "name:STRING" or "age:INT"; untyped columns have their values
+// inferred. Nodes are identified by an ":ID(namespace)" column, which
+// relationship files reference through ":START_ID(namespace)" and
+// ":END_ID(namespace)" columns.
+//
+// Rows are written with batched UNWIND queries. The server's GRAPH.BULK
+// command is not used: its binary payload format is private to the
+// bulk-insert tool and not versioned, so it cannot be targeted reliably.
+package bulk
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/snowmerak/falkordb-go/graph"
+)
+
+const defaultBatchSize = 2000
+
+// NodeFile is a CSV file of nodes.
+type NodeFile struct {
+	Path string
+	// Label is the node label, or several labels separated by colons.
+	// It defaults to the file name without its extension.
+	Label string
+}
+
+// RelationFile is a CSV file of relationships.
+type RelationFile struct {
+	Path string
+	// Type is the relationship type. It defaults to the file name without
+	// its extension.
+	Type string
+}
+
+// Progress reports the state of a load.
+type Progress struct {
+	// File is the file being loaded.
+	File string
+	// Rows is the number of data rows of File read so far.
+	Rows int
+	// Nodes, Relations and Rejected are totals across all files, including
+	// those loaded before a resumed checkpoint.
+	Nodes     int
+	Relations int
+	Rejected  int
+}
+
+// Options configures a Loader.
+type Options struct {
+	// Separator is the field separator (default ',').
+	Separator rune
+	// BatchSize is the number of rows written per query (default 2000).
+	BatchSize int
+	// Progress is called after every batch.
+	Progress func(Progress)
+	// RejectFile receives the rows that could not be converted, as CSV
+	// records of file, line, reason and the original fields.
+	RejectFile string
+	// Checkpoint is the path of a checkpoint file updated after every batch.
+	// A load started with an existing checkpoint resumes where it stopped;
+	// the last batch before an interruption may be written twice. The node
+	// ID mapping is kept in a file with the ".ids" suffix.
+	Checkpoint string
+	// Append allows loading into a graph that already exists.
+	Append bool
+}
+
+// Loader loads CSV files into a graph.
+type Loader struct {
+	g    *graph.Graph
+	opts Options
+
+	ids         map[idKey]int64
+	idLog       *idLog
+	rejects     *csv.Writer
+	rejectsFile *os.File
+	cp          *checkpoint
+}
+
+// NewLoader creates a loader writing to g.
+func NewLoader(g *graph.Graph, opts Options) *Loader {
+	if opts.Separator == 0 {
+		opts.Separator = ','
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultBatchSize
+	}
+	return &Loader{g: g, opts: opts, ids: make(map[idKey]int64)}
+}
+
+type loadFile struct {
+	path     string
+	relation bool
+	name     string
+}
+
+// Load loads the node files and then the relationship files.
+// Rows that cannot be converted are skipped and written to the reject file;
+// a failed batch stops the load.
+func (l *Loader) Load(ctx context.Context, nodes []NodeFile, relations []RelationFile) (Progress, error) {
+	files := make([]loadFile, 0, len(nodes)+len(relations))
+	names := make([]string, 0, cap(files))
+	for _, n := range nodes {
+		files = append(files, loadFile{path: n.Path, name: defaultName(n.Label, n.Path)})
+		names = append(names, "node:"+n.Path)
+	}
+	for _, r := range relations {
+		files = append(files, loadFile{path: r.Path, relation: true, name: defaultName(r.Type, r.Path)})
+		names = append(names, "relation:"+r.Path)
+	}
+
+	if err := l.open(ctx, names); err != nil {
+		return Progress{}, err
+	}
+	defer l.close()
+
+	for i := l.cp.File; i < len(files); i++ {
+		skip := l.cp.Rows
+		if err := l.loadFile(ctx, files[i], skip); err != nil {
+			return l.progress(files[i].path), err
+		}
+		l.cp.File, l.cp.Rows = i+1, 0
+		if err := l.commit(); err != nil {
+			return l.progress(files[i].path), err
+		}
+	}
+	return l.progress(""), nil
+}
+
+// open restores the checkpoint, if any, and opens the ids and reject files.
+func (l *Loader) open(ctx context.Context, names []string) error {
+	l.cp = &checkpoint{Graph: l.g.Id, Files: names}
+	resumed := false
+	if l.opts.Checkpoint != "" {
+		cp, err := readCheckpoint(l.opts.Checkpoint)
+		if err != nil {
+			return err
+		}
+		if cp != nil {
+			if cp.Graph != l.g.Id || !reflect.DeepEqual(cp.Files, names) {
+				return errors.New("checkpoint belongs to a different load")
+			}
+			l.cp, resumed = cp, true
+		}
+	}
+
+	if !resumed && !l.opts.Append {
+		n, err := l.g.Conn.Exists(ctx, l.g.Id).Result()
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return fmt.Errorf("graph %s already exists", l.g.Id)
+		}
+	}
+
+	if l.opts.Checkpoint != "" {
+		log, err := openIDLog(l.opts.Checkpoint+".ids", l.cp.IDsSize, l.ids)
+		if err != nil {
+			return err
+		}
+		l.idLog = log
+	}
+
+	if l.opts.RejectFile != "" {
+		f, err := os.OpenFile(l.opts.RejectFile, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+		if err != nil {
+			l.close()
+			return err
+		}
+		l.rejectsFile = f
+		l.rejects = csv.NewWriter(f)
+	}
+	return nil
+}
+
+func (l *Loader) close() {
+	if l.idLog != nil {
+		l.idLog.Close()
+		l.idLog = nil
+	}
+	if l.rejects != nil {
+		l.rejects.Flush()
+		l.rejectsFile.Close()
+		l.rejects, l.rejectsFile = nil, nil
+	}
+}
+
+func (l *Loader) progress(file string) Progress {
+	return Progress{
+		File:      file,
+		Rows:      l.cp.Rows,
+		Nodes:     l.cp.Nodes,
+		Relations: l.cp.Relations,
+		Rejected:  l.cp.Rejected,
+	}
+}
+
+// commit flushes the reject and ids files and saves the checkpoint.
+func (l *Loader) commit() error {
+	if l.rejects != nil {
+		l.rejects.Flush()
+		if err := l.rejects.Error(); err != nil {
+			return err
+		}
+	}
+	if l.opts.Checkpoint == "" {
+		return nil
+	}
+	size, err := l.idLog.commit()
+	if err != nil {
+		return err
+	}
+	l.cp.IDsSize = size
+	return l.cp.write(l.opts.Checkpoint)
+}
+
+func (l *Loader) reject(file string, line int, reason error, record []string) error {
+	l.cp.Rejected++
+	if l.rejects == nil {
+		return nil
+	}
+	row := append([]string{file, fmt.Sprint(line), reason.Error()}, record...)
+	return l.rejects.Write(row)
+}
+
+// batch accumulates the rows of the next query.
+type batch struct {
+	rows []interface{}
+	keys []idKey
+	// pending holds the IDs of rows in the batch, to reject duplicates
+	// before they are written.
+	pending map[idKey]struct{}
+}
+
+func (l *Loader) loadFile(ctx context.Context, file loadFile, skip int) error {
+	f, err := os.Open(file.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(bufio.NewReaderSize(f, 256*1024))
+	r.Comma = l.opts.Separator
+	r.FieldsPerRecord = -1
+
+	fields, err := r.Read()
+	if err != nil {
+		return fmt.Errorf("%s: reading header: %w", file.path, err)
+	}
+	columns, err := ParseHeader(fields)
+	if err != nil {
+		return fmt.Errorf("%s: %w", file.path, err)
+	}
+	var h *header
+	if file.relation {
+		h, err = relationHeader(columns)
+	} else {
+		h, err = nodeHeader(columns)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", file.path, err)
+	}
+
+	query := l.nodeQuery(file.name)
+	if file.relation {
+		query = l.relationQuery(file.name)
+	}
+
+	b := &batch{pending: make(map[idKey]struct{})}
+	rows := 0
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			rows++
+			if rows > skip {
+				if err := l.reject(file.path, parseErr.Line, parseErr.Err, record); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", file.path, err)
+		}
+		rows++
+		if rows <= skip {
+			continue
+		}
+
+		if file.relation {
+			err = l.addRelation(h, record, b)
+		} else {
+			err = l.addNode(h, record, b)
+		}
+		if err != nil {
+			line, _ := r.FieldPos(0)
+			if err := l.reject(file.path, line, err, record); err != nil {
+				return err
+			}
+		}
+
+		if len(b.rows) >= l.opts.BatchSize {
+			if err := l.flush(ctx, file, query, b, rows); err != nil {
+				return err
+			}
+		}
+	}
+	return l.flush(ctx, file, query, b, rows)
+}
+
+func (l *Loader) addNode(h *header, record []string, b *batch) error {
+	props, err := h.properties(record)
+	if err != nil {
+		return err
+	}
+
+	key := idKey{}
+	if h.id >= 0 {
+		key = idKey{namespace: h.columns[h.id].Namespace, id: record[h.id]}
+		if key.id == "" {
+			return errors.New("missing ID")
+		}
+		_, loaded := l.ids[key]
+		_, pending := b.pending[key]
+		if loaded || pending {
+			return fmt.Errorf("duplicate ID %q", key.id)
+		}
+		b.pending[key] = struct{}{}
+	}
+
+	b.rows = append(b.rows, props)
+	b.keys = append(b.keys, key)
+	return nil
+}
+
+func (l *Loader) addRelation(h *header, record []string, b *batch) error {
+	props, err := h.properties(record)
+	if err != nil {
+		return err
+	}
+
+	start, ok := l.ids[idKey{namespace: h.columns[h.start].Namespace, id: record[h.start]}]
+	if !ok {
+		return fmt.Errorf("unknown START_ID %q", record[h.start])
+	}
+	end, ok := l.ids[idKey{namespace: h.columns[h.end].Namespace, id: record[h.end]}]
+	if !ok {
+		return fmt.Errorf("unknown END_ID %q", record[h.end])
+	}
+
+	b.rows = append(b.rows, map[string]interface{}{"s": start, "e": end, "p": props})
+	return nil
+}
+
+// flush writes the batch and records the progress of the file up to rows.
+func (l *Loader) flush(ctx context.Context, file loadFile, query string, b *batch, rows int) error {
+	if len(b.rows) > 0 {
+		qr, err := l.exec(ctx, query, b.rows)
+		if err != nil {
+			return fmt.Errorf("%s: batch ending at row %d: %w", file.path, rows, err)
+		}
+
+		if file.relation {
+			l.cp.Relations += len(b.rows)
+		} else {
+			results := qr.Results()
+			if len(results) != len(b.rows) {
+				return fmt.Errorf("%s: expected %d node ids, got %d", file.path, len(b.rows), len(results))
+			}
+			for i, key := range b.keys {
+				if key.id == "" {
+					continue
+				}
+				id, ok := results[i].GetByIndex(0).(int64)
+				if !ok {
+					return fmt.Errorf("%s: unexpected node id %v", file.path, results[i].GetByIndex(0))
+				}
+				l.ids[key] = id
+				if l.idLog != nil {
+					if err := l.idLog.append(key, id); err != nil {
+						return err
+					}
+				}
+			}
+			l.cp.Nodes += len(b.rows)
+		}
+
+		b.rows = b.rows[:0]
+		b.keys = b.keys[:0]
+		b.pending = make(map[idKey]struct{})
+	}
+
+	l.cp.Rows = rows
+	if err := l.commit(); err != nil {
+		return err
+	}
+	if l.opts.Progress != nil {
+		l.opts.Progress(l.progress(file.path))
+	}
+	return nil
+}
+
+func (l *Loader) nodeQuery(label string) string {
+	return "UNWIND $rows AS row CREATE (n:" + quoteLabels(label) + ") SET n = row RETURN id(n)"
+}
+
+func (l *Loader) relationQuery(relation string) string {
+	return "UNWIND $rows AS row MATCH (a), (b) WHERE id(a) = row.s AND id(b) = row.e CREATE (a)-[r:" +
+		graph.QuoteIdentifier(relation) + "]->(b) SET r = row.p"
+}
+
+func (l *Loader) exec(ctx context.Context, query string, rows []interface{}) (*graph.QueryResult, error) {
+	return l.g.QueryContext(ctx, query, map[string]interface{}{"rows": rows}, nil)
+}
+
+func defaultName(name, path string) string {
+	if name != "" {
+		return name
+	}
+	base := filepath.Base(path)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+func quoteLabels(labels string) string {
+	parts := strings.Split(labels, ":")
+	for i, p := range parts {
+		parts[i] = graph.QuoteIdentifier(p)
+	}
+	return strings.Join(parts, ":")
+}
@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"math"
+	"strconv"
+)
+
+// EarthRadius is the mean radius of the Earth in meters, used for distance calculations.
+const EarthRadius = 6371008.8
+
+// Point is a geographic coordinate in degrees.
+type Point struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// NewPoint creates a Point.
+func NewPoint(latitude, longitude float64) Point {
+	return Point{Latitude: latitude, Longitude: longitude}
+}
+
+// Distance returns the great-circle distance between p and q in meters,
+// computed with the haversine formula.
+func (p Point) Distance(q Point) float64 {
+	lat1 := p.Latitude * math.Pi / 180
+	lat2 := q.Latitude * math.Pi / 180
+	dLat := lat2 - lat1
+	dLon := (q.Longitude - p.Longitude) * math.Pi / 180
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// BoundingBox returns the smallest latitude/longitude box that contains every
+// point within meters of p. Near the poles the box spans all longitudes.
+func (p Point) BoundingBox(meters float64) BoundingBox {
+	angular := meters / EarthRadius
+	lat := p.Latitude * math.Pi / 180
+
+	minLat := lat - angular
+	maxLat := lat + angular
+	minLon, maxLon := -math.Pi, math.Pi
+
+	ratio := math.Sin(angular) / math.Cos(lat)
+	if minLat > -math.Pi/2 && maxLat < math.Pi/2 && ratio < 1 {
+		dLon := math.Asin(ratio)
+		lon := p.Longitude * math.Pi / 180
+		minLon = lon - dLon
+		if minLon < -math.Pi {
+			minLon += 2 * math.Pi
+		}
+		maxLon = lon + dLon
+		if maxLon > math.Pi {
+			maxLon -= 2 * math.Pi
+		}
+	} else {
+		minLat = math.Max(minLat, -math.Pi/2)
+		maxLat = math.Min(maxLat, math.Pi/2)
+	}
+
+	return BoundingBox{
+		Min: Point{Latitude: minLat * 180 / math.Pi, Longitude: minLon * 180 / math.Pi},
+		Max: Point{Latitude: maxLat * 180 / math.Pi, Longitude: maxLon * 180 / math.Pi},
+	}
+}
+
+// String returns the point as "(latitude, longitude)".
+func (p Point) String() string {
+	return "(" + strconv.FormatFloat(p.Latitude, 'f', -1, 64) + ", " + strconv.FormatFloat(p.Longitude, 'f', -1, 64) + ")"
+}
+
+// CypherLiteral returns the point as a Cypher point() expression.
+func (p Point) CypherLiteral() string {
+	return "point({latitude: " + strconv.FormatFloat(p.Latitude, 'f', -1, 64) +
+		", longitude: " + strconv.FormatFloat(p.Longitude, 'f', -1, 64) + "})"
+}
+
+// BoundingBox is a latitude/longitude rectangle. When the box crosses the
+// antimeridian Min.Longitude is greater than Max.Longitude.
+type BoundingBox struct {
+	Min Point
+	Max Point
+}
+
+// Contains reports whether p lies inside the box.
+func (b BoundingBox) Contains(p Point) bool {
+	if p.Latitude < b.Min.Latitude || p.Latitude > b.Max.Latitude {
+		return false
+	}
+	if b.Min.Longitude <= b.Max.Longitude {
+		return p.Longitude >= b.Min.Longitude && p.Longitude <= b.Max.Longitude
+	}
+	return p.Longitude >= b.Min.Longitude || p.Longitude <= b.Max.Longitude
+}
+
+// CrossesAntimeridian reports whether the box wraps around longitude ±180.
+func (b BoundingBox) CrossesAntimeridian() bool {
+	return b.Min.Longitude > b.Max.Longitude
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPointDistance(t *testing.T) {
+	london := NewPoint(51.5074, -0.1278)
+	paris := NewPoint(48.8566, 2.3522)
+
+	got := london.Distance(paris)
+	if math.Abs(got-343_560) > 1_000 {
+		t.Fatalf("Distance = %f, want about 343.5km", got)
+	}
+	if d := london.Distance(london); d != 0 {
+		t.Fatalf("Distance to self = %f, want 0", d)
+	}
+}
+
+func TestPointBoundingBox(t *testing.T) {
+	center := NewPoint(37.0, -122.0)
+	box := center.BoundingBox(10_000)
+
+	if !box.Contains(center) {
+		t.Fatalf("box should contain its center")
+	}
+	if !box.Contains(NewPoint(37.08, -122.0)) {
+		t.Fatalf("box should contain a point 8.9km north")
+	}
+	if box.Contains(NewPoint(37.2, -122.0)) {
+		t.Fatalf("box should not contain a point 22km north")
+	}
+
+	wrapped := NewPoint(0, 179.99).BoundingBox(10_000)
+	if !wrapped.CrossesAntimeridian() {
+		t.Fatalf("box around 179.99 should cross the antimeridian: %+v", wrapped)
+	}
+	if !wrapped.Contains(NewPoint(0, -179.99)) {
+		t.Fatalf("wrapped box should contain points across the antimeridian")
+	}
+
+	polar := NewPoint(89.99, 0).BoundingBox(10_000)
+	if polar.Min.Longitude != -180 || polar.Max.Longitude != 180 || polar.Max.Latitude != 90 {
+		t.Fatalf("polar box should span all longitudes: %+v", polar)
+	}
+}
+
+func TestPointCypherLiteral(t *testing.T) {
+	if got := NewPoint(37.5, -122).CypherLiteral(); got != "point({latitude: 37.5, longitude: -122})" {
+		t.Fatalf("CypherLiteral = %q", got)
+	}
+}
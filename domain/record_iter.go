@@ -0,0 +1,16 @@
+//go:build go1.23
+
+package domain
+
+import "iter"
+
+// Fields returns an iterator over the column names and values of the record.
+func (r *Record) Fields() iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		for i, k := range r.keys {
+			if !yield(k, r.GetByIndex(i)) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,24 @@
+//go:build go1.23
+
+package domain
+
+import "testing"
+
+func TestRecordFields(t *testing.T) {
+	r := NewRecord([]interface{}{int64(1), "x"}, []string{"a", "b"})
+
+	var keys []string
+	for k, v := range r.Fields() {
+		keys = append(keys, k)
+		if got, _ := r.Get(k); got != v {
+			t.Fatalf("Fields yielded %v for %q, want %v", v, k, got)
+		}
+	}
+	if len(keys) != 2 || keys[0] != "a" || keys[1] != "b" {
+		t.Fatalf("Fields keys = %v, want [a b]", keys)
+	}
+
+	for range r.Fields() {
+		break
+	}
+}
@@ -0,0 +1,207 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Date is a calendar date without a time of day or time zone.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// NewDate creates a Date. Out of range values are normalised the way time.Date does.
+func NewDate(year int, month time.Month, day int) Date {
+	return DateOf(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+}
+
+// DateOf returns the date of t in t's location.
+func DateOf(t time.Time) Date {
+	y, m, d := t.Date()
+	return Date{Year: y, Month: m, Day: d}
+}
+
+// DateFromUnix returns the UTC date of the given Unix timestamp in seconds.
+func DateFromUnix(sec int64) Date {
+	return DateOf(time.Unix(sec, 0).UTC())
+}
+
+// In returns midnight of the date in loc.
+func (d Date) In(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+// Unix returns the Unix timestamp of midnight UTC on the date.
+func (d Date) Unix() int64 {
+	return d.In(time.UTC).Unix()
+}
+
+// String returns the date in ISO 8601 format, e.g. 2023-01-31.
+func (d Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, int(d.Month), d.Day)
+}
+
+// CypherLiteral returns the date as a Cypher date() expression.
+func (d Date) CypherLiteral() string {
+	return "date('" + d.String() + "')"
+}
+
+// LocalTime is a wall-clock time of day without a date or time zone.
+type LocalTime struct {
+	Hour       int
+	Minute     int
+	Second     int
+	Nanosecond int
+}
+
+// LocalTimeOf returns the wall-clock time of t in t's location.
+func LocalTimeOf(t time.Time) LocalTime {
+	h, m, s := t.Clock()
+	return LocalTime{Hour: h, Minute: m, Second: s, Nanosecond: t.Nanosecond()}
+}
+
+// LocalTimeFromUnix returns the UTC wall-clock time of the given Unix timestamp in seconds.
+func LocalTimeFromUnix(sec int64) LocalTime {
+	return LocalTimeOf(time.Unix(sec, 0).UTC())
+}
+
+// On returns the instant at which the time occurs on date d in loc.
+func (t LocalTime) On(d Date, loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, t.Hour, t.Minute, t.Second, t.Nanosecond, loc)
+}
+
+// SinceMidnight returns the time elapsed since midnight.
+func (t LocalTime) SinceMidnight() time.Duration {
+	return time.Duration(t.Hour)*time.Hour +
+		time.Duration(t.Minute)*time.Minute +
+		time.Duration(t.Second)*time.Second +
+		time.Duration(t.Nanosecond)
+}
+
+// String returns the time in ISO 8601 format, e.g. 13:45:00 or 13:45:00.5.
+func (t LocalTime) String() string {
+	s := fmt.Sprintf("%02d:%02d:%02d", t.Hour, t.Minute, t.Second)
+	if t.Nanosecond != 0 {
+		s += strings.TrimRight(fmt.Sprintf(".%09d", t.Nanosecond), "0")
+	}
+	return s
+}
+
+// CypherLiteral returns the time as a Cypher localtime() expression.
+func (t LocalTime) CypherLiteral() string {
+	return "localtime('" + t.String() + "')"
+}
+
+// LocalDateTime is a date and wall-clock time without a time zone.
+type LocalDateTime struct {
+	Date Date
+	Time LocalTime
+}
+
+// LocalDateTimeOf returns the date and wall-clock time of t in t's location.
+func LocalDateTimeOf(t time.Time) LocalDateTime {
+	return LocalDateTime{Date: DateOf(t), Time: LocalTimeOf(t)}
+}
+
+// LocalDateTimeFromUnix returns the UTC date and time of the given Unix timestamp in seconds.
+func LocalDateTimeFromUnix(sec int64) LocalDateTime {
+	return LocalDateTimeOf(time.Unix(sec, 0).UTC())
+}
+
+// In returns the instant at which the date and time occur in loc.
+func (dt LocalDateTime) In(loc *time.Location) time.Time {
+	return dt.Time.On(dt.Date, loc)
+}
+
+// Unix returns the Unix timestamp of the date and time interpreted as UTC.
+func (dt LocalDateTime) Unix() int64 {
+	return dt.In(time.UTC).Unix()
+}
+
+// String returns the date and time in ISO 8601 format, e.g. 2023-01-31T13:45:00.
+func (dt LocalDateTime) String() string {
+	return dt.Date.String() + "T" + dt.Time.String()
+}
+
+// CypherLiteral returns the date and time as a Cypher localdatetime() expression.
+func (dt LocalDateTime) CypherLiteral() string {
+	return "localdatetime('" + dt.String() + "')"
+}
+
+// Duration is a temporal amount split into months, days and seconds, since
+// the length of a month or day in seconds depends on the date it is applied to.
+type Duration struct {
+	Months  int64
+	Days    int64
+	Seconds int64
+	Nanos   int64
+}
+
+// DurationOf converts a time.Duration into a Duration of seconds and nanoseconds.
+func DurationOf(d time.Duration) Duration {
+	return Duration{
+		Seconds: int64(d / time.Second),
+		Nanos:   int64(d % time.Second),
+	}
+}
+
+// TimeDuration converts the duration into a time.Duration, counting a day as
+// 24 hours. It reports false if the duration has a month component, which
+// has no fixed length.
+func (d Duration) TimeDuration() (time.Duration, bool) {
+	if d.Months != 0 {
+		return 0, false
+	}
+	return time.Duration(d.Days)*24*time.Hour +
+		time.Duration(d.Seconds)*time.Second +
+		time.Duration(d.Nanos), true
+}
+
+// AddTo returns t shifted by the duration, applying months and days on the calendar.
+func (d Duration) AddTo(t time.Time) time.Time {
+	return t.AddDate(0, int(d.Months), int(d.Days)).
+		Add(time.Duration(d.Seconds)*time.Second + time.Duration(d.Nanos))
+}
+
+// String returns the duration in ISO 8601 format, e.g. P1M2DT3.5S.
+func (d Duration) String() string {
+	if d == (Duration{}) {
+		return "PT0S"
+	}
+	var sb strings.Builder
+	sb.WriteString("P")
+	if d.Months != 0 {
+		sb.WriteString(strconv.FormatInt(d.Months, 10) + "M")
+	}
+	if d.Days != 0 {
+		sb.WriteString(strconv.FormatInt(d.Days, 10) + "D")
+	}
+	if d.Seconds != 0 || d.Nanos != 0 {
+		sb.WriteString("T")
+		secs := strconv.FormatInt(d.Seconds, 10)
+		if d.Nanos != 0 {
+			nanos := d.Nanos
+			if nanos < 0 {
+				nanos = -nanos
+				if d.Seconds == 0 {
+					secs = "-0"
+				}
+			}
+			secs += strings.TrimRight(fmt.Sprintf(".%09d", nanos), "0")
+		}
+		sb.WriteString(secs + "S")
+	}
+	return sb.String()
+}
+
+// CypherLiteral returns the duration as a Cypher duration() expression.
+func (d Duration) CypherLiteral() string {
+	if d.Nanos != 0 {
+		return fmt.Sprintf("duration({months: %d, days: %d, seconds: %d, nanoseconds: %d})", d.Months, d.Days, d.Seconds, d.Nanos)
+	}
+	return fmt.Sprintf("duration({months: %d, days: %d, seconds: %d})", d.Months, d.Days, d.Seconds)
+}
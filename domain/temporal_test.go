@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDateConversions(t *testing.T) {
+	d := DateFromUnix(1672531200)
+	if d != (Date{Year: 2023, Month: time.January, Day: 1}) {
+		t.Fatalf("DateFromUnix = %v, want 2023-01-01", d)
+	}
+	if got := d.Unix(); got != 1672531200 {
+		t.Fatalf("Unix = %d, want 1672531200", got)
+	}
+
+	tokyo := time.FixedZone("JST", 9*60*60)
+	if got := DateOf(time.Date(2023, 1, 1, 1, 0, 0, 0, tokyo)); got != d {
+		t.Fatalf("DateOf uses the time's own zone: got %v", got)
+	}
+	if got := d.In(tokyo); !got.Equal(time.Date(2023, 1, 1, 0, 0, 0, 0, tokyo)) {
+		t.Fatalf("In = %v", got)
+	}
+	if got := NewDate(2023, time.February, 29); got != (Date{Year: 2023, Month: time.March, Day: 1}) {
+		t.Fatalf("NewDate should normalise, got %v", got)
+	}
+	if got := d.CypherLiteral(); got != "date('2023-01-01')" {
+		t.Fatalf("CypherLiteral = %q", got)
+	}
+}
+
+func TestLocalTimeAndDateTime(t *testing.T) {
+	lt := LocalTimeFromUnix(-2208945600)
+	if lt != (LocalTime{Hour: 12}) {
+		t.Fatalf("LocalTimeFromUnix = %v, want 12:00:00", lt)
+	}
+	if got := (LocalTime{Hour: 9, Minute: 5, Second: 7, Nanosecond: 500000000}).String(); got != "09:05:07.5" {
+		t.Fatalf("String = %q", got)
+	}
+	if got := lt.SinceMidnight(); got != 12*time.Hour {
+		t.Fatalf("SinceMidnight = %v", got)
+	}
+
+	dt := LocalDateTimeFromUnix(1672574400)
+	if got := dt.CypherLiteral(); got != "localdatetime('2023-01-01T12:00:00')" {
+		t.Fatalf("CypherLiteral = %q", got)
+	}
+	if got := dt.Unix(); got != 1672574400 {
+		t.Fatalf("Unix = %d", got)
+	}
+	if got := LocalDateTimeOf(dt.In(time.UTC)); got != dt {
+		t.Fatalf("round trip through time.Time = %v", got)
+	}
+}
+
+func TestDuration(t *testing.T) {
+	d := DurationOf(90*time.Minute + 250*time.Millisecond)
+	if d != (Duration{Seconds: 5400, Nanos: 250000000}) {
+		t.Fatalf("DurationOf = %+v", d)
+	}
+	if got, ok := d.TimeDuration(); !ok || got != 90*time.Minute+250*time.Millisecond {
+		t.Fatalf("TimeDuration = %v %v", got, ok)
+	}
+	if _, ok := (Duration{Months: 1}).TimeDuration(); ok {
+		t.Fatalf("durations with months have no fixed length")
+	}
+
+	cases := map[Duration]string{
+		{}:                          "PT0S",
+		{Months: 1, Days: 2}:        "P1M2D",
+		{Seconds: 3, Nanos: 5e8}:    "PT3.5S",
+		{Months: 14, Seconds: 3600}: "P14MT3600S",
+	}
+	for in, want := range cases {
+		if got := in.String(); got != want {
+			t.Fatalf("String(%+v) = %q, want %q", in, got, want)
+		}
+	}
+
+	start := time.Date(2023, time.January, 31, 0, 0, 0, 0, time.UTC)
+	if got := (Duration{Months: 1, Seconds: 60}).AddTo(start); !got.Equal(time.Date(2023, time.March, 3, 0, 1, 0, 0, time.UTC)) {
+		t.Fatalf("AddTo = %v", got)
+	}
+	if got := (Duration{Days: 1, Seconds: 2}).CypherLiteral(); got != "duration({months: 0, days: 1, seconds: 2})" {
+		t.Fatalf("CypherLiteral = %q", got)
+	}
+}
+
+func TestDurationCypherLiteral(t *testing.T) {
+	for d, want := range map[Duration]string{
+		{Days: 1, Seconds: 2}:                "duration({months: 0, days: 1, seconds: 2})",
+		{Seconds: 5400, Nanos: 250000000}:    "duration({months: 0, days: 0, seconds: 5400, nanoseconds: 250000000})",
+		{Months: -1, Seconds: -3, Nanos: -1}: "duration({months: -1, days: 0, seconds: -3, nanoseconds: -1})",
+	} {
+		if got := d.CypherLiteral(); got != want {
+			t.Fatalf("CypherLiteral of %+v = %q, want %q", d, got, want)
+		}
+	}
+}
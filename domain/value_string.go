@@ -12,6 +12,8 @@ func toString(i interface{}) string {
 	}
 
 	switch v := i.(type) {
+	case interface{ CypherLiteral() string }:
+		return v.CypherLiteral()
 	case string:
 		return strconv.Quote(v)
 	case int:
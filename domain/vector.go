@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"errors"
+	"math"
+
+	"github.com/snowmerak/falkordb-go/util/strs"
+)
+
+// ErrDimensionMismatch is returned when two vectors of different lengths are combined.
+var ErrDimensionMismatch = errors.New("vector dimensions do not match")
+
+// Vector is a float32 vector, as stored by the vecf32 type.
+type Vector []float32
+
+// Dot returns the dot product of v and o.
+func (v Vector) Dot(o Vector) (float64, error) {
+	if len(v) != len(o) {
+		return 0, ErrDimensionMismatch
+	}
+	var sum float64
+	for i := range v {
+		sum += float64(v[i]) * float64(o[i])
+	}
+	return sum, nil
+}
+
+// Norm returns the euclidean length of v.
+func (v Vector) Norm() float64 {
+	var sum float64
+	for _, f := range v {
+		sum += float64(f) * float64(f)
+	}
+	return math.Sqrt(sum)
+}
+
+// Normalize returns a unit length copy of v. The zero vector is returned unchanged.
+func (v Vector) Normalize() Vector {
+	out := make(Vector, len(v))
+	norm := v.Norm()
+	if norm == 0 {
+		copy(out, v)
+		return out
+	}
+	for i, f := range v {
+		out[i] = float32(float64(f) / norm)
+	}
+	return out
+}
+
+// Cosine returns the cosine similarity of v and o, in [-1, 1].
+// It returns 0 if either vector is the zero vector.
+func (v Vector) Cosine(o Vector) (float64, error) {
+	dot, err := v.Dot(o)
+	if err != nil {
+		return 0, err
+	}
+	norms := v.Norm() * o.Norm()
+	if norms == 0 {
+		return 0, nil
+	}
+	return dot / norms, nil
+}
+
+// CosineDistance returns 1 minus the cosine similarity of v and o.
+func (v Vector) CosineDistance(o Vector) (float64, error) {
+	sim, err := v.Cosine(o)
+	if err != nil {
+		return 0, err
+	}
+	return 1 - sim, nil
+}
+
+// Euclidean returns the euclidean distance between v and o.
+func (v Vector) Euclidean(o Vector) (float64, error) {
+	if len(v) != len(o) {
+		return 0, ErrDimensionMismatch
+	}
+	var sum float64
+	for i := range v {
+		d := float64(v[i]) - float64(o[i])
+		sum += d * d
+	}
+	return math.Sqrt(sum), nil
+}
+
+// AppendCypher appends the vector as a vecf32([...]) expression to dst.
+// Elements are written with the shortest representation that parses back
+// to the same float32, so the encoding is exact. It returns an error if an
+// element is NaN or infinite.
+func (v Vector) AppendCypher(dst []byte) ([]byte, error) {
+	for _, f := range v {
+		if err := strs.CheckFloat(float64(f)); err != nil {
+			return nil, err
+		}
+	}
+	return v.appendCypher(dst), nil
+}
+
+func (v Vector) appendCypher(dst []byte) []byte {
+	dst = append(dst, "vecf32(["...)
+	for i, f := range v {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = strs.AppendFloat(dst, float64(f), 32)
+	}
+	return append(dst, "])"...)
+}
+
+// CypherLiteral returns the vector as a vecf32([...]) expression. NaN and
+// infinite elements are written as is, so the result is only valid Cypher
+// when AppendCypher succeeds.
+func (v Vector) CypherLiteral() string {
+	// Most elements of an embedding encode in at most 14 bytes.
+	return string(v.appendCypher(make([]byte, 0, 10+len(v)*14)))
+}
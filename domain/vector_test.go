@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestVectorMath(t *testing.T) {
+	a := Vector{1, 0, 0}
+	b := Vector{0, 1, 0}
+
+	if dot, err := a.Dot(b); err != nil || dot != 0 {
+		t.Fatalf("Dot = %v %v, want 0", dot, err)
+	}
+	if cos, err := a.Cosine(Vector{2, 0, 0}); err != nil || cos != 1 {
+		t.Fatalf("Cosine = %v %v, want 1", cos, err)
+	}
+	if d, err := a.CosineDistance(b); err != nil || d != 1 {
+		t.Fatalf("CosineDistance = %v %v, want 1", d, err)
+	}
+	if d, err := a.Euclidean(b); err != nil || math.Abs(d-math.Sqrt2) > 1e-12 {
+		t.Fatalf("Euclidean = %v %v, want sqrt(2)", d, err)
+	}
+	if _, err := a.Dot(Vector{1}); err != ErrDimensionMismatch {
+		t.Fatalf("expected ErrDimensionMismatch, got %v", err)
+	}
+
+	n := Vector{3, 4}.Normalize()
+	if math.Abs(n.Norm()-1) > 1e-6 || n[0] != 0.6 || n[1] != 0.8 {
+		t.Fatalf("Normalize = %v", n)
+	}
+	if z := (Vector{0, 0}).Normalize(); z[0] != 0 || z[1] != 0 {
+		t.Fatalf("Normalize of zero vector = %v", z)
+	}
+}
+
+func TestVectorEncodingRoundTrips(t *testing.T) {
+	v := Vector{0.1, -1.5e-30, 3.4028235e38, 1, float32(math.Pi)}
+	lit := v.CypherLiteral()
+	if !strings.HasPrefix(lit, "vecf32([") || !strings.HasSuffix(lit, "])") {
+		t.Fatalf("CypherLiteral = %q", lit)
+	}
+
+	parts := strings.Split(strings.TrimSuffix(strings.TrimPrefix(lit, "vecf32(["), "])"), ",")
+	if len(parts) != len(v) {
+		t.Fatalf("got %d elements, want %d", len(parts), len(v))
+	}
+	for i, p := range parts {
+		f, err := strconv.ParseFloat(p, 32)
+		if err != nil {
+			t.Fatalf("element %d %q: %v", i, p, err)
+		}
+		if math.Float32bits(float32(f)) != math.Float32bits(v[i]) {
+			t.Fatalf("element %d did not round trip: %q -> %v, want %v", i, p, float32(f), v[i])
+		}
+	}
+}
+
+func TestVectorEncodingExponents(t *testing.T) {
+	lit := Vector{1e6, 3.4028235e38, 1e-7, 0.5}.CypherLiteral()
+	if lit != "vecf32([1e06,3.4028235e38,1e-07,0.5])" {
+		t.Fatalf("CypherLiteral = %q", lit)
+	}
+}
+
+func TestVectorAppendCypherRejectsNonFinite(t *testing.T) {
+	for _, f := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		if _, err := (Vector{1, float32(f)}).AppendCypher(nil); err == nil {
+			t.Fatalf("AppendCypher accepted %v", f)
+		}
+	}
+	got, err := Vector{1, 0.5}.AppendCypher([]byte("v="))
+	if err != nil || string(got) != "v=vecf32([1,0.5])" {
+		t.Fatalf("AppendCypher = %q, %v", got, err)
+	}
+}
@@ -0,0 +1,491 @@
+package falkordb
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/snowmerak/falkordb-go/graph"
+)
+
+const defaultPort = "6379"
+
+// Topology is the kind of deployment a Config connects to.
+type Topology int
+
+const (
+	TopologyStandalone Topology = iota
+	TopologyCluster
+	TopologySentinel
+)
+
+func (t Topology) String() string {
+	switch t {
+	case TopologyStandalone:
+		return "standalone"
+	case TopologyCluster:
+		return "cluster"
+	case TopologySentinel:
+		return "sentinel"
+	default:
+		return fmt.Sprintf("Topology(%d)", int(t))
+	}
+}
+
+// Config is a parsed connection string. Zero values select the driver
+// defaults.
+type Config struct {
+	Topology Topology
+	// Network is "tcp" (the default) or "unix".
+	Network string
+	// Addrs are the server, cluster seed or sentinel addresses, or the
+	// socket path.
+	Addrs []string
+	// MasterName is the sentinel service name.
+	MasterName string
+	DB         int
+	Username   string
+	Password   string
+
+	TLS                bool
+	TLSCAFile          string
+	TLSCertFile        string
+	TLSKeyFile         string
+	InsecureSkipVerify bool
+
+	ClientName   string
+	PoolSize     int
+	MinIdleConns int
+	MaxRetries   int
+	DialTimeout  time.Duration
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	PoolTimeout  time.Duration
+
+	// ReadRouting and MaxReplicaLag, in bytes, configure SetReadRouting.
+	ReadRouting   ReadRouting
+	MaxReplicaLag int64
+	// QueryTimeout is the default query timeout in milliseconds.
+	QueryTimeout int
+	ReadOnly     bool
+}
+
+// Open connects to the deployment described by dsn, see ParseDSN.
+func Open(dsn string) (*FalkorDB, error) {
+	cfg, err := ParseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return OpenConfig(cfg)
+}
+
+// ParseDSN parses a connection string:
+//
+//	falkor://[user:password@]host[:port][/db][?option=value&...]
+//	falkor://host1:port,host2:port[?...]            (cluster)
+//	falkor+cluster://host[:port][?...]              (cluster with one seed)
+//	falkor+sentinel://host1,host2/master-name[/db][?...]
+//	falkor+unix:///path/to/socket[?db=N&...]
+//
+// The falkors schemes (falkors://, falkors+cluster://, ...) enable TLS, and
+// redis and rediss are accepted in place of falkor and falkors. Ports
+// default to 6379, or 26379 for sentinels. The options are pool_size,
+// min_idle_conns, max_retries, dial_timeout, read_timeout, write_timeout,
+// pool_timeout, client_name, read_routing (master, replica, nearest or
+// random), max_replica_lag (bytes), query_timeout (milliseconds), readonly,
+// tls_ca, tls_cert, tls_key and insecure_skip_verify. Durations are Go
+// durations or a number of seconds.
+func ParseDSN(dsn string) (*Config, error) {
+	scheme, rest, ok := strings.Cut(dsn, "://")
+	if !ok {
+		return nil, errors.New("connection string has no scheme")
+	}
+
+	cfg := &Config{Network: "tcp"}
+	base, variant, _ := strings.Cut(scheme, "+")
+	switch base {
+	case "falkor", "redis":
+	case "falkors", "rediss":
+		cfg.TLS = true
+	default:
+		return nil, fmt.Errorf("unsupported scheme %q", scheme)
+	}
+
+	rest, query, _ := strings.Cut(rest, "?")
+	switch variant {
+	case "":
+	case "cluster":
+		cfg.Topology = TopologyCluster
+	case "sentinel":
+		cfg.Topology = TopologySentinel
+	case "unix":
+		cfg.Network = "unix"
+		if rest == "" {
+			return nil, errors.New("connection string has no socket path")
+		}
+		cfg.Addrs = []string{rest}
+	default:
+		return nil, fmt.Errorf("unsupported scheme %q", scheme)
+	}
+
+	if cfg.Network == "tcp" {
+		hosts, path, _ := strings.Cut(rest, "/")
+		if at := strings.LastIndex(hosts, "@"); at >= 0 {
+			if err := cfg.parseUserinfo(hosts[:at]); err != nil {
+				return nil, err
+			}
+			hosts = hosts[at+1:]
+		}
+		if hosts == "" {
+			return nil, errors.New("connection string has no host")
+		}
+
+		port := defaultPort
+		if cfg.Topology == TopologySentinel {
+			port = defaultSentinelPort
+		}
+		for _, h := range strings.Split(hosts, ",") {
+			if _, _, err := net.SplitHostPort(h); err != nil {
+				h = net.JoinHostPort(strings.Trim(h, "[]"), port)
+			}
+			cfg.Addrs = append(cfg.Addrs, h)
+		}
+		if len(cfg.Addrs) > 1 && cfg.Topology == TopologyStandalone {
+			cfg.Topology = TopologyCluster
+		}
+
+		if cfg.Topology == TopologySentinel {
+			name, rest, _ := strings.Cut(path, "/")
+			name, err := url.PathUnescape(name)
+			if err != nil {
+				return nil, err
+			}
+			if name == "" {
+				return nil, errors.New("sentinel connection string has no master name")
+			}
+			cfg.MasterName, path = name, rest
+		}
+		if path != "" {
+			if cfg.Topology == TopologyCluster {
+				return nil, errors.New("cluster connection string cannot select a database")
+			}
+			db, err := strconv.Atoi(path)
+			if err != nil {
+				return nil, fmt.Errorf("invalid database number %q", path)
+			}
+			cfg.DB = db
+		}
+	}
+
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.parseOptions(values); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) parseUserinfo(userinfo string) error {
+	user, pass, hasPass := strings.Cut(userinfo, ":")
+	var err error
+	if c.Username, err = url.PathUnescape(user); err != nil {
+		return err
+	}
+	if hasPass {
+		if c.Password, err = url.PathUnescape(pass); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Config) parseOptions(values url.Values) error {
+	for key, vals := range values {
+		v := vals[len(vals)-1]
+		var err error
+		switch key {
+		case "db":
+			c.DB, err = strconv.Atoi(v)
+		case "pool_size":
+			c.PoolSize, err = strconv.Atoi(v)
+		case "min_idle_conns":
+			c.MinIdleConns, err = strconv.Atoi(v)
+		case "max_retries":
+			c.MaxRetries, err = strconv.Atoi(v)
+		case "dial_timeout":
+			c.DialTimeout, err = parseDuration(v)
+		case "read_timeout":
+			c.ReadTimeout, err = parseDuration(v)
+		case "write_timeout":
+			c.WriteTimeout, err = parseDuration(v)
+		case "pool_timeout":
+			c.PoolTimeout, err = parseDuration(v)
+		case "client_name":
+			c.ClientName = v
+		case "read_routing":
+			c.ReadRouting, err = parseReadRouting(v)
+		case "max_replica_lag":
+			c.MaxReplicaLag, err = strconv.ParseInt(v, 10, 64)
+		case "query_timeout":
+			c.QueryTimeout, err = strconv.Atoi(v)
+		case "readonly":
+			c.ReadOnly, err = strconv.ParseBool(v)
+		case "tls_ca":
+			c.TLSCAFile = v
+		case "tls_cert":
+			c.TLSCertFile = v
+		case "tls_key":
+			c.TLSKeyFile = v
+		case "insecure_skip_verify":
+			c.InsecureSkipVerify, err = strconv.ParseBool(v)
+		default:
+			return fmt.Errorf("unknown connection option %q", key)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid value %q for connection option %s: %w", v, key, err)
+		}
+	}
+	return nil
+}
+
+func parseDuration(v string) (time.Duration, error) {
+	if n, err := strconv.Atoi(v); err == nil {
+		return time.Duration(n) * time.Second, nil
+	}
+	return time.ParseDuration(v)
+}
+
+func parseReadRouting(v string) (ReadRouting, error) {
+	for _, r := range []ReadRouting{ReadMaster, ReadReplica, ReadNearest, ReadRandom} {
+		if v == r.String() {
+			return r, nil
+		}
+	}
+	return ReadMaster, errors.New("unknown read routing")
+}
+
+// DSN returns the connection string of c, which ParseDSN parses back into
+// an equal Config.
+func (c *Config) DSN() string {
+	return c.format(false)
+}
+
+// String returns the connection string of c with the password redacted.
+func (c *Config) String() string {
+	return c.format(true)
+}
+
+func (c *Config) format(redact bool) string {
+	var b strings.Builder
+	if c.TLS {
+		b.WriteString("falkors")
+	} else {
+		b.WriteString("falkor")
+	}
+
+	values := url.Values{}
+	if c.Network == "unix" {
+		b.WriteString("+unix://")
+		if len(c.Addrs) > 0 {
+			b.WriteString(c.Addrs[0])
+		}
+		if c.DB != 0 {
+			values.Set("db", strconv.Itoa(c.DB))
+		}
+	} else {
+		switch c.Topology {
+		case TopologyCluster:
+			b.WriteString("+cluster")
+		case TopologySentinel:
+			b.WriteString("+sentinel")
+		}
+		b.WriteString("://")
+		if c.Username != "" || c.Password != "" {
+			password := c.Password
+			if redact && password != "" {
+				password = "xxxxx"
+			}
+			if password != "" {
+				b.WriteString(url.UserPassword(c.Username, password).String())
+			} else {
+				b.WriteString(url.User(c.Username).String())
+			}
+			b.WriteByte('@')
+		}
+		b.WriteString(strings.Join(c.Addrs, ","))
+		if c.Topology == TopologySentinel {
+			b.WriteByte('/')
+			b.WriteString(url.PathEscape(c.MasterName))
+		}
+		if c.DB != 0 {
+			b.WriteByte('/')
+			b.WriteString(strconv.Itoa(c.DB))
+		}
+	}
+
+	setInt := func(key string, v int) {
+		if v != 0 {
+			values.Set(key, strconv.Itoa(v))
+		}
+	}
+	setDuration := func(key string, v time.Duration) {
+		if v != 0 {
+			values.Set(key, v.String())
+		}
+	}
+	setString := func(key, v string) {
+		if v != "" {
+			values.Set(key, v)
+		}
+	}
+	setInt("pool_size", c.PoolSize)
+	setInt("min_idle_conns", c.MinIdleConns)
+	setInt("max_retries", c.MaxRetries)
+	setDuration("dial_timeout", c.DialTimeout)
+	setDuration("read_timeout", c.ReadTimeout)
+	setDuration("write_timeout", c.WriteTimeout)
+	setDuration("pool_timeout", c.PoolTimeout)
+	setString("client_name", c.ClientName)
+	if c.ReadRouting != ReadMaster {
+		values.Set("read_routing", c.ReadRouting.String())
+	}
+	if c.MaxReplicaLag != 0 {
+		values.Set("max_replica_lag", strconv.FormatInt(c.MaxReplicaLag, 10))
+	}
+	setInt("query_timeout", c.QueryTimeout)
+	if c.ReadOnly {
+		values.Set("readonly", "true")
+	}
+	setString("tls_ca", c.TLSCAFile)
+	setString("tls_cert", c.TLSCertFile)
+	setString("tls_key", c.TLSKeyFile)
+	if c.InsecureSkipVerify {
+		values.Set("insecure_skip_verify", "true")
+	}
+
+	if len(values) > 0 {
+		b.WriteByte('?')
+		b.WriteString(values.Encode())
+	}
+	return b.String()
+}
+
+// tlsConfig builds the TLS configuration of c, or nil when TLS is off.
+func (c *Config) tlsConfig() (*tls.Config, error) {
+	if !c.TLS {
+		return nil, nil
+	}
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+	if c.TLSCAFile != "" {
+		pem, err := os.ReadFile(c.TLSCAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", c.TLSCAFile)
+		}
+		cfg.RootCAs = pool
+	}
+	if c.TLSCertFile != "" || c.TLSKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.TLSCertFile, c.TLSKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// OpenConfig connects to the deployment described by cfg.
+func OpenConfig(cfg *Config) (*FalkorDB, error) {
+	if len(cfg.Addrs) == 0 {
+		return nil, errors.New("no address to connect to")
+	}
+	tlsConfig, err := cfg.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	var db *FalkorDB
+	switch cfg.Topology {
+	case TopologyCluster:
+		db, err = newCluster(&ConnectionClusterOption{
+			Addrs:        cfg.Addrs,
+			ClientName:   cfg.ClientName,
+			Username:     cfg.Username,
+			Password:     cfg.Password,
+			MaxRetries:   cfg.MaxRetries,
+			DialTimeout:  cfg.DialTimeout,
+			ReadTimeout:  cfg.ReadTimeout,
+			WriteTimeout: cfg.WriteTimeout,
+			PoolSize:     cfg.PoolSize,
+			PoolTimeout:  cfg.PoolTimeout,
+			MinIdleConns: cfg.MinIdleConns,
+			TLSConfig:    tlsConfig,
+		}, cfg.ReadOnly)
+	case TopologySentinel:
+		db, err = newSentinel(&SentinelOption{
+			MasterName:       cfg.MasterName,
+			SentinelAddrs:    cfg.Addrs,
+			ClientName:       cfg.ClientName,
+			SentinelUsername: cfg.Username,
+			SentinelPassword: cfg.Password,
+			Username:         cfg.Username,
+			Password:         cfg.Password,
+			DB:               cfg.DB,
+			MaxRetries:       cfg.MaxRetries,
+			DialTimeout:      cfg.DialTimeout,
+			ReadTimeout:      cfg.ReadTimeout,
+			WriteTimeout:     cfg.WriteTimeout,
+			PoolSize:         cfg.PoolSize,
+			PoolTimeout:      cfg.PoolTimeout,
+			MinIdleConns:     cfg.MinIdleConns,
+			TLSConfig:        tlsConfig,
+		}, cfg.ReadOnly)
+	default:
+		db, err = new(&ConnectionOption{
+			Network:      cfg.Network,
+			Addr:         cfg.Addrs[0],
+			ClientName:   cfg.ClientName,
+			Username:     cfg.Username,
+			Password:     cfg.Password,
+			DB:           cfg.DB,
+			MaxRetries:   cfg.MaxRetries,
+			DialTimeout:  cfg.DialTimeout,
+			ReadTimeout:  cfg.ReadTimeout,
+			WriteTimeout: cfg.WriteTimeout,
+			PoolSize:     cfg.PoolSize,
+			PoolTimeout:  cfg.PoolTimeout,
+			MinIdleConns: cfg.MinIdleConns,
+			TLSConfig:    tlsConfig,
+		}, cfg.ReadOnly)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.QueryTimeout > 0 {
+		db.defaults.Options = graph.NewQueryOptions().SetTimeout(cfg.QueryTimeout)
+	}
+	if cfg.ReadRouting != ReadMaster {
+		err := db.SetReadRouting(&ReadRoutingOptions{Routing: cfg.ReadRouting, MaxLagBytes: cfg.MaxReplicaLag})
+		if err != nil {
+			_ = db.Conn.Close()
+			return nil, err
+		}
+	}
+	return db, nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/redis/go-redis/v9"
 
@@ -17,6 +18,17 @@ var ctx = context.Background()
 type FalkorDB struct {
 	Conn     redis.UniversalClient
 	readonly bool
+	failover *SentinelOption
+	reads    *readRouter
+	defaults graph.Defaults
+	breaker  *breaker
+	life     lifecycle
+
+	capsOnce sync.Once
+	caps     *graph.CapabilityCache
+
+	watcherOnce sync.Once
+	watcher     *failoverWatcher
 }
 
 type ConnectionOption = redis.Options
@@ -45,7 +57,7 @@ func new(options *ConnectionOption, isReadonly bool) (*FalkorDB, error) {
 			return nil, fmt.Errorf("unexpected sentinel masters type %T", mastersRaw)
 		}
 		if len(masters) != 1 {
-			return nil, errors.New("multiple masters, require service name")
+			return nil, errors.New("multiple masters, use NewSentinel with a master name")
 		}
 		m0, ok := masters[0].(map[interface{}]interface{})
 		if !ok {
@@ -59,22 +71,13 @@ func new(options *ConnectionOption, isReadonly bool) (*FalkorDB, error) {
 		if !ok {
 			return nil, errors.New("sentinel master name not string")
 		}
-		db = redis.NewFailoverClient(&redis.FailoverOptions{
-			MasterName:       masterName,
-			SentinelAddrs:    []string{options.Addr},
-			ClientName:       options.ClientName,
-			Username:         options.Username,
-			Password:         options.Password,
-			SentinelUsername: options.Username,
-			SentinelPassword: options.Password,
-			MaxRetries:       options.MaxRetries,
-			MinRetryBackoff:  options.MinRetryBackoff,
-			MaxRetryBackoff:  options.MaxRetryBackoff,
-			TLSConfig:        options.TLSConfig,
-			PoolFIFO:         options.PoolFIFO,
-			PoolSize:         options.PoolSize,
-			PoolTimeout:      options.PoolTimeout,
-		})
+		failover := failoverOptions(options, masterName)
+		_ = db.Close()
+		return &FalkorDB{
+			Conn:     redis.NewFailoverClient(failover),
+			readonly: isReadonly,
+			failover: failover,
+		}, nil
 	}
 	return &FalkorDB{
 		Conn:     db,
@@ -93,25 +96,37 @@ func NewReadOnly(options *ConnectionOption) (*FalkorDB, error) {
 }
 
 // NewCluster creates a new FalkorDB cluster instance.
+// When ReadOnly, RouteByLatency or RouteRandomly is set in options, read only
+// queries are routed to replicas as with SetReadRouting using ReadReplica,
+// ReadNearest or ReadRandom.
 func NewCluster(options *ConnectionClusterOption) (*FalkorDB, error) {
-	db := redis.NewClusterClient(options)
-	return &FalkorDB{
-		Conn:     db,
-		readonly: false,
-	}, nil
+	return newCluster(options, false)
 }
 
 // NewClusterReadOnly creates a new read-only FalkorDB cluster instance.
 func NewClusterReadOnly(options *ConnectionClusterOption) (*FalkorDB, error) {
-	db := redis.NewClusterClient(options)
-	return &FalkorDB{
-		Conn:     db,
-		readonly: true,
-	}, nil
+	return newCluster(options, true)
+}
+
+func newCluster(options *ConnectionClusterOption, isReadonly bool) (*FalkorDB, error) {
+	db := &FalkorDB{
+		Conn:     redis.NewClusterClient(options),
+		readonly: isReadonly,
+	}
+	if routing := clusterReadRouting(options); routing != ReadMaster {
+		if err := db.SetReadRouting(&ReadRoutingOptions{Routing: routing}); err != nil {
+			return nil, err
+		}
+	}
+	return db, nil
 }
 
 // Creates a new FalkorDB instance from a URL.
+// falkor+sentinel:// and falkors+sentinel:// URLs are passed to FromSentinelURL.
 func FromURL(url string) (*FalkorDB, error) {
+	if strings.HasPrefix(url, "falkor+sentinel://") || strings.HasPrefix(url, "falkors+sentinel://") {
+		return FromSentinelURL(url)
+	}
 	url = strings.ReplaceAll(url, "falkors://", "rediss://")
 	url = strings.ReplaceAll(url, "falkor://", "redis://")
 
@@ -124,6 +139,9 @@ func FromURL(url string) (*FalkorDB, error) {
 }
 
 func FromClusterURL(urls string) (*FalkorDB, error) {
+	urls = strings.ReplaceAll(urls, "falkors://", "rediss://")
+	urls = strings.ReplaceAll(urls, "falkor://", "redis://")
+
 	options, err := redis.ParseClusterURL(urls)
 	if err != nil {
 		return nil, err
@@ -133,12 +151,65 @@ func FromClusterURL(urls string) (*FalkorDB, error) {
 }
 
 // Selects a graph by creating a new Graph instance.
+// The graph inherits the client defaults.
 func (db *FalkorDB) SelectGraph(graphName string) *graph.Graph {
-	return graph.NewWithMode(graphName, db.Conn, db.readonly)
+	g := graph.NewWithMode(graphName, db.Conn, db.readonly)
+	if db.reads != nil {
+		g.SetReadRouter(db.reads)
+	}
+	g.SetCapabilityCache(db.capabilities())
+	return g.SetTracker(&db.life).SetClientHooks(db.clientHooks()...).SetDefaults(db.defaults)
+}
+
+func (db *FalkorDB) capabilities() *graph.CapabilityCache {
+	db.capsOnce.Do(func() {
+		db.caps = graph.NewCapabilityCache(db.Conn)
+	})
+	return db.caps
+}
+
+// Capabilities returns the capabilities of the server. The module version is
+// detected once per client.
+func (db *FalkorDB) Capabilities() (graph.Capabilities, error) {
+	return db.capabilities().Get(ctx)
+}
+
+// clientHooks returns the client's own hooks, which wrap the queries of
+// every selected graph.
+func (db *FalkorDB) clientHooks() []graph.QueryHook {
+	if db.breaker == nil {
+		return nil
+	}
+	return []graph.QueryHook{db.breaker.hook}
+}
+
+// SelectGraphWithDefaults selects a graph whose defaults are the client
+// defaults overridden by overrides, see graph.Defaults.Merge.
+func (db *FalkorDB) SelectGraphWithDefaults(graphName string, overrides graph.Defaults) *graph.Graph {
+	g := db.SelectGraph(graphName)
+	return g.SetDefaults(db.defaults.Merge(overrides))
+}
+
+// Defaults returns the defaults inherited by selected graphs.
+func (db *FalkorDB) Defaults() graph.Defaults {
+	return db.defaults
+}
+
+// SetDefaults sets the defaults inherited by graphs selected afterwards.
+func (db *FalkorDB) SetDefaults(defaults graph.Defaults) {
+	db.defaults = defaults
+}
+
+// readOnly reports whether the client rejects writes.
+func (db *FalkorDB) readOnly() bool {
+	return db.readonly || db.defaults.ReadOnly
 }
 
 // CopyGraph copies a graph to a new key.
 func (db *FalkorDB) CopyGraph(src, dest string) error {
+	if err := db.capabilities().Require(ctx, graph.FeatureCopy); err != nil {
+		return err
+	}
 	return db.Conn.Do(ctx, "GRAPH.COPY", src, dest).Err()
 }
 
@@ -172,11 +243,17 @@ func (db *FalkorDB) runOnAllMasters(args ...interface{}) error {
 
 // LoadUDF loads a user defined function library.
 func (db *FalkorDB) LoadUDF(libraryName, code string) error {
+	if err := db.capabilities().Require(ctx, graph.FeatureUDF); err != nil {
+		return err
+	}
 	return db.runOnAllMasters("GRAPH.UDF", "LOAD", libraryName, code)
 }
 
 // LoadUDFReplace loads a user defined function library, replacing it if it already exists.
 func (db *FalkorDB) LoadUDFReplace(libraryName, code string) error {
+	if err := db.capabilities().Require(ctx, graph.FeatureUDF); err != nil {
+		return err
+	}
 	return db.runOnAllMasters("GRAPH.UDF", "LOAD", "REPLACE", libraryName, code)
 }
 
@@ -234,6 +311,10 @@ func WithUDFCode() UDFListOption {
 // ListUDF lists loaded user defined function libraries.
 // It accepts optional arguments to filter by library name and to include source code.
 func (db *FalkorDB) ListUDF(opts ...UDFListOption) ([]UDFLibrary, error) {
+	if err := db.capabilities().Require(ctx, graph.FeatureUDF); err != nil {
+		return nil, err
+	}
+
 	options := &UDFListOptions{}
 	for _, opt := range opts {
 		opt(options)
@@ -292,10 +373,16 @@ func (db *FalkorDB) ListUDF(opts ...UDFListOption) ([]UDFLibrary, error) {
 
 // DeleteUDF removes a user defined function library.
 func (db *FalkorDB) DeleteUDF(libraryName string) error {
+	if err := db.capabilities().Require(ctx, graph.FeatureUDF); err != nil {
+		return err
+	}
 	return db.runOnAllMasters("GRAPH.UDF", "DELETE", libraryName)
 }
 
 // FlushUDFs removes all user defined function libraries.
 func (db *FalkorDB) FlushUDFs() error {
+	if err := db.capabilities().Require(ctx, graph.FeatureUDF); err != nil {
+		return err
+	}
 	return db.runOnAllMasters("GRAPH.UDF", "FLUSH")
 }
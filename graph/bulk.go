@@ -0,0 +1,268 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/snowmerak/falkordb-go/util/strs"
+)
+
+const (
+	defaultBulkChunkSize     = 1000
+	defaultBulkChunkBytes    = 1 << 20
+	defaultBulkPipelineDepth = 8
+)
+
+// BulkOptions controls how bulk writes are split into chunks. Zero values
+// select the defaults.
+type BulkOptions struct {
+	// ChunkSize is the maximum number of rows per chunk (default 1000).
+	ChunkSize int
+	// ChunkBytes is the maximum encoded size of the rows of a chunk
+	// (default 1 MiB). A single larger row is sent as a chunk of its own.
+	ChunkBytes int
+	// PipelineDepth is the number of chunks sent per round trip (default 8).
+	PipelineDepth int
+	// Options are applied to every chunk query.
+	Options *QueryOptions
+}
+
+func (o *BulkOptions) withDefaults() BulkOptions {
+	var out BulkOptions
+	if o != nil {
+		out = *o
+	}
+	if out.ChunkSize <= 0 {
+		out.ChunkSize = defaultBulkChunkSize
+	}
+	if out.ChunkBytes <= 0 {
+		out.ChunkBytes = defaultBulkChunkBytes
+	}
+	if out.PipelineDepth <= 0 {
+		out.PipelineDepth = defaultBulkPipelineDepth
+	}
+	return out
+}
+
+// BulkChunkError reports the chunk of a bulk write that failed. Chunks before
+// it have been applied; chunks sent in the same round trip after it may have
+// been applied as well and are included in the returned statistics. When a
+// row cannot be encoded, the rows of its chunk before it are sent as the
+// chunk and nothing after it is sent.
+type BulkChunkError struct {
+	// Chunk is the zero based index of the chunk.
+	Chunk int
+	// Offset is the index of the first input row of the chunk.
+	Offset int
+	// Count is the number of rows sent in the chunk.
+	Count int
+	Err   error
+}
+
+func (e *BulkChunkError) Error() string {
+	if e.Count == 0 {
+		return fmt.Sprintf("bulk chunk %d (no rows sent): %v", e.Chunk, e.Err)
+	}
+	return fmt.Sprintf("bulk chunk %d (rows %d-%d): %v", e.Chunk, e.Offset, e.Offset+e.Count-1, e.Err)
+}
+
+func (e *BulkChunkError) Unwrap() error {
+	return e.Err
+}
+
+// EdgeEndpoint identifies the nodes an edge connects by label and key properties.
+type EdgeEndpoint struct {
+	Label string
+	Keys  []string
+}
+
+// BulkEdge is an edge between the nodes whose key properties equal From and To.
+type BulkEdge struct {
+	From       map[string]interface{}
+	To         map[string]interface{}
+	Properties map[string]interface{}
+}
+
+// BulkCreateNodes creates a node with the given label for every property map
+// in rows, sending them in pipelined UNWIND chunks.
+func (g *Graph) BulkCreateNodes(ctx context.Context, label string, rows []map[string]interface{}, opts *BulkOptions) (QueryStatistics, error) {
+	query := fmt.Sprintf("UNWIND $rows AS row CREATE (n:%s) SET n = row", QuoteIdentifier(label))
+	return g.bulkWrite(ctx, query, len(rows), func(i int) interface{} { return nonNilMap(rows[i]) }, opts)
+}
+
+// BulkMergeNodes merges a node with the given label for every property map in
+// rows, matching existing nodes on the keys properties, and sets the
+// remaining properties.
+func (g *Graph) BulkMergeNodes(ctx context.Context, label string, keys []string, rows []map[string]interface{}, opts *BulkOptions) (QueryStatistics, error) {
+	if len(keys) == 0 {
+		return QueryStatistics{}, errors.New("merge keys are required")
+	}
+	query := fmt.Sprintf("UNWIND $rows AS row MERGE (n:%s {%s}) SET n += row",
+		QuoteIdentifier(label), keyPattern("row", keys))
+	return g.bulkWrite(ctx, query, len(rows), func(i int) interface{} { return nonNilMap(rows[i]) }, opts)
+}
+
+// BulkCreateEdges creates an edge of type relation for every entry of edges.
+// Endpoints are matched by their key properties; edges whose endpoints do not
+// exist are skipped, which shows as a lower RelationshipsCreated count.
+func (g *Graph) BulkCreateEdges(ctx context.Context, relation string, from, to EdgeEndpoint, edges []BulkEdge, opts *BulkOptions) (QueryStatistics, error) {
+	if len(from.Keys) == 0 || len(to.Keys) == 0 {
+		return QueryStatistics{}, errors.New("endpoint keys are required")
+	}
+	query := fmt.Sprintf("UNWIND $rows AS row MATCH (a:%s {%s}) MATCH (b:%s {%s}) CREATE (a)-[e:%s]->(b) SET e = row.p",
+		QuoteIdentifier(from.Label), keyPattern("row.f", from.Keys),
+		QuoteIdentifier(to.Label), keyPattern("row.t", to.Keys),
+		QuoteIdentifier(relation))
+	return g.bulkWrite(ctx, query, len(edges), func(i int) interface{} {
+		return map[string]interface{}{
+			"f": nonNilMap(edges[i].From),
+			"t": nonNilMap(edges[i].To),
+			"p": nonNilMap(edges[i].Properties),
+		}
+	}, opts)
+}
+
+// keyPattern builds the property map of a MATCH or MERGE pattern that binds
+// every key to the same property of source.
+func keyPattern(source string, keys []string) string {
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = QuoteIdentifier(k) + ": " + source + "." + QuoteIdentifier(k)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func nonNilMap(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return map[string]interface{}{}
+	}
+	return m
+}
+
+type bulkChunk struct {
+	index  int
+	offset int
+	count  int
+	query  string
+}
+
+// bulkChunker encodes rows into chunk queries of bounded size.
+type bulkChunker struct {
+	query string
+	n     int
+	row   func(i int) interface{}
+	opts  BulkOptions
+
+	next  int
+	index int
+	buf   []byte
+}
+
+// nextChunk encodes the next chunk. When a row cannot be encoded, the
+// returned chunk holds the rows before it, if any, together with the error.
+func (c *bulkChunker) nextChunk() (bulkChunk, error) {
+	chunk := bulkChunk{index: c.index, offset: c.next}
+	c.index++
+
+	buf := append(c.buf[:0], "CYPHER rows=["...)
+	start := len(buf)
+	var encodeErr error
+	for c.next < c.n && chunk.count < c.opts.ChunkSize {
+		mark := len(buf)
+		if chunk.count > 0 {
+			buf = append(buf, ',')
+		}
+		encoded, err := strs.AppendValue(buf, c.row(c.next))
+		if err != nil {
+			buf = buf[:mark]
+			encodeErr = fmt.Errorf("row %d: %w", c.next, err)
+			break
+		}
+		buf = encoded
+		if chunk.count > 0 && len(buf)-start > c.opts.ChunkBytes {
+			buf = buf[:mark]
+			break
+		}
+		chunk.count++
+		c.next++
+	}
+	buf = append(buf, "] "...)
+	buf = append(buf, c.query...)
+	chunk.query = string(buf)
+	c.buf = buf
+	return chunk, encodeErr
+}
+
+// bulkWrite sends n rows through query in pipelined chunks and sums the
+// statistics of the chunks that succeeded.
+func (g *Graph) bulkWrite(ctx context.Context, query string, n int, row func(i int) interface{}, opts *BulkOptions) (QueryStatistics, error) {
+	var stats QueryStatistics
+	if g.ReadOnly() {
+		return stats, ErrReadOnly
+	}
+	if _, err := g.enter(); err != nil {
+		return stats, err
+	}
+	defer g.leave()
+
+	o := opts.withDefaults()
+	chunker := &bulkChunker{query: query, n: n, row: row, opts: o}
+
+	for chunker.next < n {
+		var chunks []bulkChunk
+		var encodeErr error
+		for len(chunks) < o.PipelineDepth && chunker.next < n {
+			chunk, err := chunker.nextChunk()
+			if chunk.count > 0 {
+				chunks = append(chunks, chunk)
+			}
+			if err != nil {
+				encodeErr = &BulkChunkError{Chunk: chunk.index, Offset: chunk.offset, Count: chunk.count, Err: err}
+				break
+			}
+		}
+
+		if err := g.execBulkChunks(ctx, chunks, o.Options, &stats); err != nil {
+			return stats, err
+		}
+		if encodeErr != nil {
+			return stats, encodeErr
+		}
+	}
+	return stats, nil
+}
+
+func (g *Graph) execBulkChunks(ctx context.Context, chunks []bulkChunk, options *QueryOptions, stats *QueryStatistics) error {
+	if len(chunks) == 0 {
+		return nil
+	}
+
+	pipe := g.Conn.Pipeline()
+	cmds := make([]*redis.Cmd, len(chunks))
+	for i, chunk := range chunks {
+		args, _ := g.commandArgs(CmdQuery, chunk.query, nil, options) // no params, cannot fail
+		cmds[i] = pipe.Do(ctx, args...)
+	}
+	_, _ = pipe.Exec(ctx)
+
+	var first error
+	for i, cmd := range cmds {
+		err := cmd.Err()
+		var qr *QueryResult
+		if err == nil {
+			qr, err = QueryResultNew(g, cmd.Val())
+		}
+		if err != nil {
+			if first == nil {
+				first = &BulkChunkError{Chunk: chunks[i].index, Offset: chunks[i].offset, Count: chunks[i].count, Err: err}
+			}
+			continue
+		}
+		stats.Add(qr.stats)
+	}
+	return first
+}
@@ -0,0 +1,109 @@
+package graph
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func chunkAll(t *testing.T, c *bulkChunker) []bulkChunk {
+	t.Helper()
+	var chunks []bulkChunk
+	for c.next < c.n {
+		chunk, err := c.nextChunk()
+		require.NoError(t, err)
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+func TestBulkChunkerRows(t *testing.T) {
+	c := &bulkChunker{
+		query: "UNWIND $rows AS row RETURN row",
+		n:     5,
+		row:   func(i int) interface{} { return i },
+		opts:  (&BulkOptions{ChunkSize: 2}).withDefaults(),
+	}
+	chunks := chunkAll(t, c)
+	require.Len(t, chunks, 3)
+	assert.Equal(t, "CYPHER rows=[0,1] UNWIND $rows AS row RETURN row", chunks[0].query)
+	assert.Equal(t, "CYPHER rows=[4] UNWIND $rows AS row RETURN row", chunks[2].query)
+	assert.Equal(t, 1, chunks[1].index)
+	assert.Equal(t, 2, chunks[1].offset)
+	assert.Equal(t, 1, chunks[2].count)
+}
+
+func TestBulkChunkerBytes(t *testing.T) {
+	long := strings.Repeat("x", 8)
+	c := &bulkChunker{
+		query: "RETURN 1",
+		n:     3,
+		row:   func(i int) interface{} { return long },
+		opts:  (&BulkOptions{ChunkBytes: 25}).withDefaults(),
+	}
+	chunks := chunkAll(t, c)
+	require.Len(t, chunks, 2)
+	assert.Equal(t, 2, chunks[0].count)
+	assert.Equal(t, `CYPHER rows=["xxxxxxxx","xxxxxxxx"] RETURN 1`, chunks[0].query)
+	assert.Equal(t, 1, chunks[1].count)
+
+	// a single oversized row still makes a chunk
+	c = &bulkChunker{query: "RETURN 1", n: 1, row: func(i int) interface{} { return long }, opts: (&BulkOptions{ChunkBytes: 1}).withDefaults()}
+	assert.Len(t, chunkAll(t, c), 1)
+}
+
+func TestBulkChunkerUnsupportedValue(t *testing.T) {
+	c := &bulkChunker{
+		query: "RETURN 1",
+		n:     3,
+		row: func(i int) interface{} {
+			if i == 1 {
+				return map[string]interface{}{"bad": struct{}{}}
+			}
+			return i
+		},
+		opts: (&BulkOptions{}).withDefaults(),
+	}
+	chunk, err := c.nextChunk()
+	assert.EqualError(t, err, "row 1: unsupported value type struct {}")
+	assert.Equal(t, 1, chunk.count)
+	assert.Equal(t, "CYPHER rows=[0] RETURN 1", chunk.query)
+	assert.Equal(t, 1, c.next)
+}
+
+func TestBulkQueries(t *testing.T) {
+	assert.Equal(t, "`id`: row.f.`id`, `tenant`: row.f.`tenant`", keyPattern("row.f", []string{"id", "tenant"}))
+
+	g := NewWithMode("g", nil, true)
+	_, err := g.BulkCreateNodes(context.Background(), "Person", []map[string]interface{}{{"name": "a"}}, nil)
+	assert.EqualError(t, err, "graph is read-only")
+
+	_, err = g.BulkMergeNodes(context.Background(), "Person", nil, nil, nil)
+	assert.EqualError(t, err, "merge keys are required")
+}
+
+func TestBulkChunkError(t *testing.T) {
+	err := &BulkChunkError{Chunk: 3, Offset: 3000, Count: 1000, Err: assert.AnError}
+	assert.Equal(t, "bulk chunk 3 (rows 3000-3999): "+assert.AnError.Error(), err.Error())
+	assert.ErrorIs(t, err, assert.AnError)
+
+	err = &BulkChunkError{Chunk: 0, Offset: 0, Count: 0, Err: assert.AnError}
+	assert.Equal(t, "bulk chunk 0 (no rows sent): "+assert.AnError.Error(), err.Error())
+}
+
+func TestBulkChunkerQuotesKeys(t *testing.T) {
+	c := &bulkChunker{
+		query: "UNWIND $rows AS row CREATE (n) SET n = row",
+		n:     1,
+		row: func(i int) interface{} {
+			return map[string]interface{}{"x}) DETACH DELETE n //": 1}
+		},
+		opts: (&BulkOptions{}).withDefaults(),
+	}
+	chunk, err := c.nextChunk()
+	require.NoError(t, err)
+	assert.Equal(t, "CYPHER rows=[{`x}) DETACH DELETE n //`: 1}] UNWIND $rows AS row CREATE (n) SET n = row", chunk.query)
+}
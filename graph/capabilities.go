@@ -0,0 +1,144 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Feature is a server feature that needs a minimum module version. Only
+// commands are gated: the scalar type ids of result sets are not, since the
+// protocol only appends new ids, and an id this client does not know is
+// reported with its number when parsing.
+type Feature struct {
+	Name string
+	// MinVersion is the first module version supporting the feature, such as
+	// 41000 for 4.10.0.
+	MinVersion int
+}
+
+var (
+	// FeatureCopy is the GRAPH.COPY command, first released in FalkorDB
+	// v4.0.0.
+	FeatureCopy = Feature{Name: "GRAPH.COPY", MinVersion: 40000}
+	// FeatureMemoryUsage is the GRAPH.MEMORY USAGE command, first released
+	// in FalkorDB v4.10.0.
+	FeatureMemoryUsage = Feature{Name: "GRAPH.MEMORY", MinVersion: 41000}
+	// FeatureUDF is the GRAPH.UDF command family, first released in
+	// FalkorDB v4.12.0.
+	FeatureUDF = Feature{Name: "GRAPH.UDF", MinVersion: 41200}
+)
+
+// ErrUnsupportedByServer is matched by errors for features the server does
+// not support; see UnsupportedError.
+var ErrUnsupportedByServer = errors.New("unsupported by server")
+
+// UnsupportedError reports a feature that needs a newer module version.
+type UnsupportedError struct {
+	Feature       Feature
+	ServerVersion int
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("%s requires FalkorDB %s or later, the server runs %s",
+		e.Feature.Name, FormatVersion(e.Feature.MinVersion), FormatVersion(e.ServerVersion))
+}
+
+func (e *UnsupportedError) Is(target error) bool {
+	return target == ErrUnsupportedByServer
+}
+
+// FormatVersion formats a module version such as 41002 as "4.10.2".
+func FormatVersion(v int) string {
+	return fmt.Sprintf("%d.%d.%d", v/10000, v/100%100, v%100)
+}
+
+// Capabilities describes what the server supports.
+type Capabilities struct {
+	ModuleVersion int
+}
+
+// Supports reports whether the server supports f.
+func (c Capabilities) Supports(f Feature) bool {
+	return c.ModuleVersion >= f.MinVersion
+}
+
+// Require returns an *UnsupportedError if the server does not support f.
+func (c Capabilities) Require(f Feature) error {
+	if c.Supports(f) {
+		return nil
+	}
+	return &UnsupportedError{Feature: f, ServerVersion: c.ModuleVersion}
+}
+
+// CapabilityCache detects the capabilities of a server once and remembers
+// them. An error replied by the server, such as MODULE LIST being denied to
+// the user, or a server without the module is remembered too; other failed
+// detections, such as network errors, are retried on the next call.
+type CapabilityCache struct {
+	conn redis.UniversalClient
+
+	mu       sync.Mutex
+	caps     Capabilities
+	err      error
+	detected bool
+}
+
+// NewCapabilityCache creates a cache detecting the capabilities of the
+// server conn sends commands to.
+func NewCapabilityCache(conn redis.UniversalClient) *CapabilityCache {
+	return &CapabilityCache{conn: conn}
+}
+
+// Get returns the capabilities of the server, detecting them on first use.
+func (c *CapabilityCache) Get(ctx context.Context) (Capabilities, error) {
+	if c == nil {
+		return Capabilities{}, errors.New("graph has no connection to detect capabilities")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.detected {
+		return c.caps, c.err
+	}
+	version, err := ServerModuleVersion(ctx, c.conn)
+	if err != nil {
+		err = fmt.Errorf("detecting server capabilities: %w", err)
+		var rerr redis.Error
+		if errors.As(err, &rerr) || errors.Is(err, ErrModuleNotLoaded) {
+			c.err, c.detected = err, true
+		}
+		return Capabilities{}, err
+	}
+	c.caps = Capabilities{ModuleVersion: version}
+	c.detected = true
+	return c.caps, nil
+}
+
+// Require returns an *UnsupportedError if the server does not support f, or
+// the detection error if the version cannot be detected, for example because
+// MODULE LIST is not allowed for the user.
+func (c *CapabilityCache) Require(ctx context.Context, f Feature) error {
+	if c == nil {
+		return nil
+	}
+	caps, err := c.Get(ctx)
+	if err != nil {
+		return err
+	}
+	return caps.Require(f)
+}
+
+// SetCapabilityCache shares c with the graph, so that the server version is
+// detected once for all graphs of a connection.
+func (g *Graph) SetCapabilityCache(c *CapabilityCache) *Graph {
+	g.caps = c
+	return g
+}
+
+// Capabilities returns the capabilities of the server.
+func (g *Graph) Capabilities(ctx context.Context) (Capabilities, error) {
+	return g.caps.Get(ctx)
+}
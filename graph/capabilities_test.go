@@ -0,0 +1,76 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatVersion(t *testing.T) {
+	assert.Equal(t, "4.10.2", FormatVersion(41002))
+	assert.Equal(t, "4.0.0", FormatVersion(40000))
+	assert.Equal(t, "0.0.0", FormatVersion(0))
+}
+
+func TestCapabilitiesRequire(t *testing.T) {
+	caps := Capabilities{ModuleVersion: 41000}
+	assert.True(t, caps.Supports(FeatureCopy))
+	assert.True(t, caps.Supports(FeatureMemoryUsage))
+	assert.False(t, caps.Supports(FeatureUDF))
+	assert.NoError(t, caps.Require(FeatureMemoryUsage))
+
+	err := caps.Require(FeatureUDF)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrUnsupportedByServer))
+	var uerr *UnsupportedError
+	require.True(t, errors.As(err, &uerr))
+	assert.Equal(t, FeatureUDF, uerr.Feature)
+	assert.Equal(t, 41000, uerr.ServerVersion)
+	assert.Equal(t, "GRAPH.UDF requires FalkorDB 4.12.0 or later, the server runs 4.10.0", err.Error())
+}
+
+func TestCapabilityCacheErrors(t *testing.T) {
+	conn := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	defer conn.Close()
+	c := NewCapabilityCache(conn)
+
+	_, err := c.Get(context.Background())
+	assert.Error(t, err)
+	err = c.Require(context.Background(), FeatureUDF)
+	assert.ErrorContains(t, err, "detecting server capabilities")
+	assert.False(t, errors.Is(err, ErrUnsupportedByServer))
+
+	var nilCache *CapabilityCache
+	assert.NoError(t, nilCache.Require(context.Background(), FeatureUDF))
+
+	g := NewGraphWithSchema(GraphSchemaWithData(nil, nil, nil))
+	_, err = g.Capabilities(context.Background())
+	assert.Error(t, err)
+}
+
+func TestCapabilityCacheRemembersServerErrors(t *testing.T) {
+	for name, reply := range map[string]func() (interface{}, error){
+		"denied": func() (interface{}, error) {
+			return nil, testRedisError("NOPERM this user has no permissions to run the 'module|list' command")
+		},
+		"not loaded": func() (interface{}, error) { return []interface{}{}, nil },
+	} {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			g := hookedGraph(t, func(args []interface{}) (interface{}, error) {
+				calls++
+				return reply()
+			})
+
+			for i := 0; i < 2; i++ {
+				err := g.caps.Require(context.Background(), FeatureUDF)
+				assert.ErrorContains(t, err, "detecting server capabilities")
+			}
+			assert.Equal(t, 1, calls)
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package graph
+
+import (
+	"context"
+)
+
+// QueryFunc executes a query request against a graph.
+type QueryFunc func(ctx context.Context, g *Graph, req QueryRequest) (*QueryResult, error)
+
+// QueryHook wraps the execution of queries, for logging, metrics or tracing.
+// A hook runs the query by calling next.
+type QueryHook func(next QueryFunc) QueryFunc
+
+// Defaults are the settings a graph applies to every query. FalkorDB has no
+// per-query memory limit, only the server wide QUERY_MEM_CAPACITY
+// configuration, so there is no memory limit among them.
+type Defaults struct {
+	// Options are merged under the options passed to every query.
+	Options *QueryOptions
+	// ReadOnly rejects writes with ErrReadOnly.
+	ReadOnly bool
+	// Retry retries failed read only queries; nil disables retries.
+	Retry *RetryPolicy
+	// Hooks wrap every query, the first hook outermost.
+	Hooks []QueryHook
+}
+
+// Merge returns d overridden by o: o.Options are merged over d.Options, the
+// graph is read-only if either is, o.Retry replaces d.Retry when set, and
+// o.Hooks run inside d.Hooks.
+func (d Defaults) Merge(o Defaults) Defaults {
+	merged := Defaults{
+		Options:  d.Options.Merge(o.Options),
+		ReadOnly: d.ReadOnly || o.ReadOnly,
+		Retry:    d.Retry,
+		Hooks:    append(append([]QueryHook(nil), d.Hooks...), o.Hooks...),
+	}
+	if o.Retry != nil {
+		merged.Retry = o.Retry
+	}
+	return merged
+}
+
+// Defaults returns the defaults of the graph.
+func (g *Graph) Defaults() Defaults {
+	return g.defaults
+}
+
+// SetDefaults replaces the defaults of the graph.
+func (g *Graph) SetDefaults(d Defaults) *Graph {
+	g.defaults = d
+	return g
+}
+
+// SetClientHooks sets hooks that wrap every query outside of the hooks of
+// the defaults. They belong to the client owning the connection and are
+// kept by SetDefaults.
+func (g *Graph) SetClientHooks(hooks ...QueryHook) *Graph {
+	g.hooks = hooks
+	return g
+}
+
+// Tracker admits the work run on graphs and tracks it until it is done, so
+// that a client can wait for it when it shuts down.
+type Tracker interface {
+	// Enter admits work on the graph, or returns an error to reject it. The
+	// returned context is done once the client stops waiting for the work.
+	Enter(graphName string) (context.Context, error)
+	// Leave reports that work admitted by Enter is done.
+	Leave(graphName string)
+}
+
+// SetTracker sets the tracker of the client owning the connection. Queries,
+// pipelines, transactions, bulk writes, snapshots and streams enter it while
+// they run, and are rejected with its error once it refuses them.
+func (g *Graph) SetTracker(t Tracker) *Graph {
+	g.tracker = t
+	return g
+}
+
+// enter admits work on the graph with the tracker, if any. The context is
+// nil without a tracker.
+func (g *Graph) enter() (context.Context, error) {
+	if g.tracker == nil {
+		return nil, nil
+	}
+	return g.tracker.Enter(g.Id)
+}
+
+func (g *Graph) leave() {
+	if g.tracker != nil {
+		g.tracker.Leave(g.Id)
+	}
+}
+
+// run executes req through the hooks and the retry policy of the graph.
+func (g *Graph) run(ctx context.Context, req QueryRequest) (*QueryResult, error) {
+	if _, err := g.enter(); err != nil {
+		return nil, err
+	}
+	defer g.leave()
+
+	fn := QueryFunc(func(ctx context.Context, g *Graph, req QueryRequest) (*QueryResult, error) {
+		return g.retry(ctx, req)
+	})
+	for i := len(g.defaults.Hooks) - 1; i >= 0; i-- {
+		fn = g.defaults.Hooks[i](fn)
+	}
+	for i := len(g.hooks) - 1; i >= 0; i-- {
+		fn = g.hooks[i](fn)
+	}
+	return fn(ctx, g, req)
+}
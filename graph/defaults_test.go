@@ -0,0 +1,101 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryOptionsMerge(t *testing.T) {
+	var unset *QueryOptions
+	assert.Equal(t, -1, unset.Merge(nil).GetTimeout())
+	assert.Equal(t, 10, NewQueryOptions().SetTimeout(10).Merge(nil).GetTimeout())
+	assert.Equal(t, 10, NewQueryOptions().SetTimeout(10).Merge(NewQueryOptions()).GetTimeout())
+	assert.Equal(t, 20, NewQueryOptions().SetTimeout(10).Merge(NewQueryOptions().SetTimeout(20)).GetTimeout())
+	assert.Equal(t, 20, unset.Merge(NewQueryOptions().SetTimeout(20)).GetTimeout())
+
+	base := NewQueryOptions().SetTimeout(10)
+	base.Merge(NewQueryOptions().SetTimeout(20))
+	assert.Equal(t, 10, base.GetTimeout())
+}
+
+func TestDefaultsMerge(t *testing.T) {
+	retry := &RetryPolicy{MaxRetries: 2, Backoff: time.Millisecond}
+	client := Defaults{Options: NewQueryOptions().SetTimeout(100), Retry: retry}
+
+	merged := client.Merge(Defaults{ReadOnly: true})
+	assert.Equal(t, 100, merged.Options.GetTimeout())
+	assert.True(t, merged.ReadOnly)
+	assert.Same(t, retry, merged.Retry)
+
+	other := &RetryPolicy{}
+	merged = merged.Merge(Defaults{Options: NewQueryOptions().SetTimeout(5), Retry: other})
+	assert.Equal(t, 5, merged.Options.GetTimeout())
+	assert.True(t, merged.ReadOnly)
+	assert.Same(t, other, merged.Retry)
+}
+
+func TestDefaultsCommandArgs(t *testing.T) {
+	g := NewWithMode("g", nil, false)
+	g.SetDefaults(Defaults{Options: NewQueryOptions().SetTimeout(100)})
+
+	assert.Equal(t, []interface{}{CmdQuery, "g", "RETURN 1", "--compact", "timeout", 100}, testArgs(t, g, CmdQuery, "RETURN 1", nil))
+	assert.Equal(t, []interface{}{CmdQuery, "g", "RETURN 1", "--compact", "timeout", 5}, testArgs(t, g, CmdQuery, "RETURN 1", NewQueryOptions().SetTimeout(5)))
+}
+
+func TestDefaultsReadOnly(t *testing.T) {
+	g := NewWithMode("g", nil, false)
+	assert.False(t, g.ReadOnly())
+	g.SetDefaults(Defaults{ReadOnly: true})
+	assert.True(t, g.ReadOnly())
+
+	_, err := g.Query("CREATE ()", nil, nil)
+	assert.ErrorIs(t, err, ErrReadOnly)
+}
+
+func TestQueryHooks(t *testing.T) {
+	var calls []string
+	hook := func(name string) QueryHook {
+		return func(next QueryFunc) QueryFunc {
+			return func(ctx context.Context, g *Graph, req QueryRequest) (*QueryResult, error) {
+				calls = append(calls, name+" "+g.Id+" "+req.Query)
+				if name == "inner" {
+					return nil, errors.New("short-circuit")
+				}
+				return next(ctx, g, req)
+			}
+		}
+	}
+
+	g := NewWithMode("g", nil, false)
+	g.SetDefaults(Defaults{Hooks: []QueryHook{hook("outer")}}.Merge(Defaults{Hooks: []QueryHook{hook("inner")}}))
+
+	_, err := g.ROQuery("RETURN 1", nil, nil)
+	assert.EqualError(t, err, "short-circuit")
+	assert.Equal(t, []string{"outer g RETURN 1", "inner g RETURN 1"}, calls)
+}
+
+func TestClientHooksSurviveSetDefaults(t *testing.T) {
+	var calls []string
+	hook := func(name string) QueryHook {
+		return func(next QueryFunc) QueryFunc {
+			return func(ctx context.Context, g *Graph, req QueryRequest) (*QueryResult, error) {
+				calls = append(calls, name)
+				if name == "defaults" {
+					return nil, errors.New("short-circuit")
+				}
+				return next(ctx, g, req)
+			}
+		}
+	}
+
+	g := NewWithMode("g", nil, false).SetClientHooks(hook("client"))
+	g.SetDefaults(Defaults{Hooks: []QueryHook{hook("defaults")}})
+
+	_, err := g.ROQuery("RETURN 1", nil, nil)
+	assert.EqualError(t, err, "short-circuit")
+	assert.Equal(t, []string{"client", "defaults"}, calls)
+}
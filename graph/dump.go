@@ -0,0 +1,458 @@
+package graph
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/snowmerak/falkordb-go/domain"
+	"github.com/snowmerak/falkordb-go/util/strs"
+)
+
+const (
+	// ImportLabel is the temporary label a Cypher dump puts on every node so
+	// that edges can find their endpoints by ImportIDProperty.
+	ImportLabel = "__Import"
+	// ImportIDProperty is the temporary property holding a node's original id.
+	ImportIDProperty = "__import_id"
+	// ConstraintDirective starts a dump line holding the JSON encoded
+	// arguments of a GRAPH.CONSTRAINT command, without the graph key.
+	// Constraints cannot be created from Cypher.
+	ConstraintDirective = "// GRAPH.CONSTRAINT "
+)
+
+const defaultDumpBatchSize = 500
+
+// DumpOptions configures DumpCypher.
+type DumpOptions struct {
+	// BatchSize is the number of nodes or edges per CREATE statement.
+	BatchSize int
+}
+
+type DumpOption func(*DumpOptions)
+
+// WithDumpBatchSize sets the number of nodes or edges per statement.
+func WithDumpBatchSize(n int) DumpOption {
+	return func(o *DumpOptions) {
+		o.BatchSize = n
+	}
+}
+
+// DumpCypher writes the graph to w as a Cypher script with one statement per
+// line: index creation, batched node and edge creation, removal of the
+// temporary import label and property, and finally constraints, which are
+// created last so the restored data is validated once. Nodes and edges are
+// written in id order, so dumping the same graph twice gives the same script.
+// Index options other than the vector dimension and similarity function are
+// not preserved.
+func (g *Graph) DumpCypher(ctx context.Context, w io.Writer, opts ...DumpOption) error {
+	o := DumpOptions{BatchSize: defaultDumpBatchSize}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = defaultDumpBatchSize
+	}
+
+	d := &dumper{w: bufio.NewWriter(w), batchSize: o.BatchSize}
+	d.line("// FalkorDB Cypher dump of graph " + strconv.Quote(g.Id))
+
+	indexes, err := g.query(ctx, CmdROQuery, "CALL db.indexes()", nil, nil)
+	if err != nil {
+		return err
+	}
+	for _, r := range indexes.results {
+		for _, stmt := range indexStatements(r) {
+			d.statement(stmt)
+		}
+	}
+	d.statement(fmt.Sprintf("CREATE INDEX FOR (n:%s) ON (n.%s)", QuoteIdentifier(ImportLabel), QuoteIdentifier(ImportIDProperty)))
+	if d.err != nil {
+		return d.err
+	}
+
+	if err := g.dumpEntities(ctx, "MATCH (n) RETURN n ORDER BY id(n)", func(v interface{}) error {
+		n, ok := v.(*domain.Node)
+		if !ok {
+			return fmt.Errorf("unexpected node type %T", v)
+		}
+		return d.node(n)
+	}); err != nil {
+		return err
+	}
+	if err := d.flushNodes(); err != nil {
+		return err
+	}
+
+	if err := g.dumpEntities(ctx, "MATCH ()-[e]->() RETURN e ORDER BY id(e)", func(v interface{}) error {
+		e, ok := v.(*domain.Edge)
+		if !ok {
+			return fmt.Errorf("unexpected edge type %T", v)
+		}
+		return d.edge(e)
+	}); err != nil {
+		return err
+	}
+	if err := d.flushEdges(); err != nil {
+		return err
+	}
+
+	d.statement(fmt.Sprintf("MATCH (n:%[1]s) REMOVE n.%[2]s, n:%[1]s", QuoteIdentifier(ImportLabel), QuoteIdentifier(ImportIDProperty)))
+	d.statement(fmt.Sprintf("DROP INDEX ON :%s(%s)", QuoteIdentifier(ImportLabel), QuoteIdentifier(ImportIDProperty)))
+
+	constraints, err := g.query(ctx, CmdROQuery, "CALL db.constraints()", nil, nil)
+	if err != nil {
+		return err
+	}
+	for _, r := range constraints.results {
+		if args := constraintArgs(r); args != nil {
+			b, _ := json.Marshal(args)
+			d.line(ConstraintDirective + string(b))
+		}
+	}
+	if d.err != nil {
+		return d.err
+	}
+	return d.w.Flush()
+}
+
+func (g *Graph) dumpEntities(ctx context.Context, query string, fn func(interface{}) error) error {
+	s, err := g.ROQueryStream(ctx, query, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	for s.Next() {
+		if err := fn(s.Record().GetByIndex(0)); err != nil {
+			return err
+		}
+	}
+	return s.Err()
+}
+
+// dumper buffers nodes with the same labels and edges with the same type
+// into batched statements. err is the first error writing to w, which stops
+// the dump at the next batch.
+type dumper struct {
+	w         *bufio.Writer
+	err       error
+	batchSize int
+	buf       []byte
+
+	labels string
+	nodes  []*domain.Node
+	edges  []*domain.Edge
+}
+
+func (d *dumper) line(s string) {
+	d.write(s, "\n")
+}
+
+func (d *dumper) statement(s string) {
+	d.write(s, ";\n")
+}
+
+func (d *dumper) write(s, end string) {
+	if d.err != nil {
+		return
+	}
+	if _, d.err = d.w.WriteString(s); d.err == nil {
+		_, d.err = d.w.WriteString(end)
+	}
+}
+
+func (d *dumper) node(n *domain.Node) error {
+	if d.err != nil {
+		return d.err
+	}
+	labels := ""
+	for _, l := range n.Labels {
+		labels += ":" + QuoteIdentifier(l)
+	}
+	if labels != d.labels || len(d.nodes) >= d.batchSize {
+		if err := d.flushNodes(); err != nil {
+			return err
+		}
+	}
+	d.labels = labels
+	d.nodes = append(d.nodes, n)
+	return nil
+}
+
+func (d *dumper) flushNodes() error {
+	if len(d.nodes) == 0 {
+		return nil
+	}
+	buf := append(d.buf[:0], "UNWIND ["...)
+	for i, n := range d.nodes {
+		if i > 0 {
+			buf = append(buf, ", "...)
+		}
+		var err error
+		if buf, err = appendDumpMap(buf, n.Properties, int64(n.ID)); err != nil {
+			return fmt.Errorf("node %d: %w", n.ID, err)
+		}
+	}
+	buf = append(buf, "] AS p CREATE (n:"...)
+	buf = append(buf, QuoteIdentifier(ImportLabel)...)
+	buf = append(buf, d.labels...)
+	buf = append(buf, ") SET n = p;\n"...)
+	d.buf = buf
+	d.nodes = d.nodes[:0]
+	if d.err == nil {
+		_, d.err = d.w.Write(buf)
+	}
+	return d.err
+}
+
+func (d *dumper) edge(e *domain.Edge) error {
+	if d.err != nil {
+		return d.err
+	}
+	if len(d.edges) > 0 && (e.Relation != d.edges[0].Relation || len(d.edges) >= d.batchSize) {
+		if err := d.flushEdges(); err != nil {
+			return err
+		}
+	}
+	d.edges = append(d.edges, e)
+	return nil
+}
+
+func (d *dumper) flushEdges() error {
+	if len(d.edges) == 0 {
+		return nil
+	}
+	buf := append(d.buf[:0], "UNWIND ["...)
+	for i, e := range d.edges {
+		if i > 0 {
+			buf = append(buf, ", "...)
+		}
+		buf = append(buf, '[')
+		buf = strconv.AppendUint(buf, e.SrcNodeID, 10)
+		buf = append(buf, ", "...)
+		buf = strconv.AppendUint(buf, e.DestNodeID, 10)
+		buf = append(buf, ", "...)
+		var err error
+		if buf, err = appendDumpMap(buf, e.Properties, -1); err != nil {
+			return fmt.Errorf("edge %d: %w", e.ID, err)
+		}
+		buf = append(buf, ']')
+	}
+	imported := QuoteIdentifier(ImportLabel)
+	id := QuoteIdentifier(ImportIDProperty)
+	buf = append(buf, "] AS e MATCH (a:"+imported+" {"+id+": e[0]}), (b:"+imported+" {"+id+": e[1]}) CREATE (a)-[r:"...)
+	buf = append(buf, QuoteIdentifier(d.edges[0].Relation)...)
+	buf = append(buf, "]->(b) SET r = e[2];\n"...)
+	d.buf = buf
+	d.edges = d.edges[:0]
+	if d.err == nil {
+		_, d.err = d.w.Write(buf)
+	}
+	return d.err
+}
+
+// appendDumpMap appends props as a map literal with quoted, sorted keys.
+// A non-negative importID is added as the import id property.
+func appendDumpMap(dst []byte, props map[string]interface{}, importID int64) ([]byte, error) {
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	dst = append(dst, '{')
+	if importID >= 0 {
+		dst = append(dst, QuoteIdentifier(ImportIDProperty)...)
+		dst = append(dst, ": "...)
+		dst = strconv.AppendInt(dst, importID, 10)
+	}
+	for i, k := range keys {
+		if i > 0 || importID >= 0 {
+			dst = append(dst, ", "...)
+		}
+		dst = append(dst, QuoteIdentifier(k)...)
+		dst = append(dst, ": "...)
+		var err error
+		if dst, err = appendDumpValue(dst, props[k]); err != nil {
+			return nil, fmt.Errorf("property %s: %w", k, err)
+		}
+	}
+	return append(dst, '}'), nil
+}
+
+// appendDumpValue appends v as a Cypher literal that restores to the same
+// type: floats always get a decimal point or an exponent, and strings use
+// Cypher escapes only.
+func appendDumpValue(dst []byte, v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case nil:
+		return append(dst, "null"...), nil
+	case bool:
+		return strconv.AppendBool(dst, v), nil
+	case int64:
+		return strconv.AppendInt(dst, v, 10), nil
+	case int:
+		return strconv.AppendInt(dst, int64(v), 10), nil
+	case float64:
+		if err := strs.CheckFloat(v); err != nil {
+			return nil, err
+		}
+		start := len(dst)
+		dst = strs.AppendFloat(dst, v, 64)
+		if !bytes.ContainsAny(dst[start:], ".e") {
+			dst = append(dst, ".0"...)
+		}
+		return dst, nil
+	case string:
+		return appendDumpString(dst, v), nil
+	case []interface{}:
+		dst = append(dst, '[')
+		for i, e := range v {
+			if i > 0 {
+				dst = append(dst, ", "...)
+			}
+			var err error
+			if dst, err = appendDumpValue(dst, e); err != nil {
+				return nil, err
+			}
+		}
+		return append(dst, ']'), nil
+	case map[string]interface{}:
+		return appendDumpMap(dst, v, -1)
+	case strs.CypherAppender:
+		return v.AppendCypher(dst)
+	case strs.CypherValue:
+		return append(dst, v.CypherLiteral()...), nil
+	default:
+		return nil, fmt.Errorf("cannot dump value of type %T", v)
+	}
+}
+
+// appendDumpString appends s as a double quoted Cypher string. Cypher has no
+// \x, \a or \v escapes, so other control characters are written as \uXXXX.
+func appendDumpString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case '"', '\\':
+			dst = append(dst, '\\', c)
+		case '\b':
+			dst = append(dst, `\b`...)
+		case '\f':
+			dst = append(dst, `\f`...)
+		case '\n':
+			dst = append(dst, `\n`...)
+		case '\r':
+			dst = append(dst, `\r`...)
+		case '\t':
+			dst = append(dst, `\t`...)
+		default:
+			if c < 0x20 || c == 0x7f {
+				dst = append(dst, `\u00`...)
+				dst = append(dst, "0123456789abcdef"[c>>4], "0123456789abcdef"[c&0xf])
+			} else {
+				dst = append(dst, c)
+			}
+		}
+	}
+	return append(dst, '"')
+}
+
+// indexStatements returns the statements recreating a db.indexes() entry.
+func indexStatements(r *domain.Record) []string {
+	label, _ := r.Get("label")
+	entity, _ := r.Get("entitytype")
+	types, _ := r.Get("types")
+	options, _ := r.Get("options")
+
+	name, ok := label.(string)
+	typeMap, ok2 := types.(map[string]interface{})
+	if !ok || !ok2 {
+		return nil
+	}
+	pattern := "(n:" + QuoteIdentifier(name) + ")"
+	if entity == "RELATIONSHIP" {
+		pattern = "()-[n:" + QuoteIdentifier(name) + "]-()"
+	}
+	optionMap, _ := options.(map[string]interface{})
+
+	props := make([]string, 0, len(typeMap))
+	for p := range typeMap {
+		props = append(props, p)
+	}
+	sort.Strings(props)
+
+	var stmts []string
+	for _, p := range props {
+		kinds, _ := typeMap[p].([]interface{})
+		for _, kind := range kinds {
+			on := " ON (n." + QuoteIdentifier(p) + ")"
+			switch kind {
+			case "RANGE":
+				stmts = append(stmts, "CREATE INDEX FOR "+pattern+on)
+			case "FULLTEXT":
+				stmts = append(stmts, "CREATE FULLTEXT INDEX FOR "+pattern+on)
+			case "VECTOR":
+				if opts := vectorIndexOptions(optionMap, p); opts != "" {
+					stmts = append(stmts, "CREATE VECTOR INDEX FOR "+pattern+on+" OPTIONS "+opts)
+				}
+			}
+		}
+	}
+	return stmts
+}
+
+// vectorIndexOptions finds the dimension and similarity function of a vector
+// index, either at the top level of options or under the property name.
+func vectorIndexOptions(options map[string]interface{}, prop string) string {
+	if nested, ok := options[prop].(map[string]interface{}); ok {
+		options = nested
+	}
+	dim, ok := options["dimension"].(int64)
+	if !ok {
+		return ""
+	}
+	fn, ok := options["similarityFunction"].(string)
+	if !ok {
+		return ""
+	}
+	return "{dimension: " + strconv.FormatInt(dim, 10) + ", similarityFunction: " + strconv.Quote(fn) + "}"
+}
+
+// constraintArgs returns the GRAPH.CONSTRAINT CREATE arguments, without the
+// graph key, recreating a db.constraints() entry.
+func constraintArgs(r *domain.Record) []string {
+	kind, _ := r.Get("type")
+	label, _ := r.Get("label")
+	entity, _ := r.Get("entitytype")
+	props, _ := r.Get("properties")
+
+	k, ok1 := kind.(string)
+	l, ok2 := label.(string)
+	e, ok3 := entity.(string)
+	list, ok4 := props.([]interface{})
+	if !ok1 || !ok2 || !ok3 || !ok4 || len(list) == 0 {
+		return nil
+	}
+	if e != "RELATIONSHIP" {
+		e = "NODE"
+	}
+
+	args := []string{"CREATE", strings.ToUpper(k), e, l, "PROPERTIES", strconv.Itoa(len(list))}
+	for _, p := range list {
+		s, ok := p.(string)
+		if !ok {
+			return nil
+		}
+		args = append(args, s)
+	}
+	return args
+}
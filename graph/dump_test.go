@@ -0,0 +1,123 @@
+package graph
+
+import (
+	"bufio"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/snowmerak/falkordb-go/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDumperBatches(t *testing.T) {
+	var out strings.Builder
+	d := &dumper{w: bufio.NewWriter(&out), batchSize: 2}
+
+	d.node(&domain.Node{ID: 0, Labels: []string{"Person"}, Properties: map[string]interface{}{"name": "Ann", "age": int64(31)}})
+	d.node(&domain.Node{ID: 1, Labels: []string{"Person"}, Properties: map[string]interface{}{}})
+	d.node(&domain.Node{ID: 2, Labels: []string{"Person"}, Properties: map[string]interface{}{"name": "it's"}})
+	d.node(&domain.Node{ID: 3, Properties: map[string]interface{}{"p": domain.NewPoint(1, 2)}})
+	d.flushNodes()
+
+	d.edge(&domain.Edge{Relation: "KNOWS", SrcNodeID: 0, DestNodeID: 1, Properties: map[string]interface{}{"since": int64(2020)}})
+	d.edge(&domain.Edge{Relation: "LIVES IN", SrcNodeID: 1, DestNodeID: 3, Properties: map[string]interface{}{}})
+	d.flushEdges()
+	d.w.Flush()
+
+	assert.Equal(t, strings.Join([]string{
+		"UNWIND [{`__import_id`: 0, `age`: 31, `name`: \"Ann\"}, {`__import_id`: 1}] AS p CREATE (n:`__Import`:`Person`) SET n = p;",
+		"UNWIND [{`__import_id`: 2, `name`: \"it's\"}] AS p CREATE (n:`__Import`:`Person`) SET n = p;",
+		"UNWIND [{`__import_id`: 3, `p`: point({latitude: 1, longitude: 2})}] AS p CREATE (n:`__Import`) SET n = p;",
+		"UNWIND [[0, 1, {`since`: 2020}]] AS e MATCH (a:`__Import` {`__import_id`: e[0]}), (b:`__Import` {`__import_id`: e[1]}) CREATE (a)-[r:`KNOWS`]->(b) SET r = e[2];",
+		"UNWIND [[1, 3, {}]] AS e MATCH (a:`__Import` {`__import_id`: e[0]}), (b:`__Import` {`__import_id`: e[1]}) CREATE (a)-[r:`LIVES IN`]->(b) SET r = e[2];",
+		"",
+	}, "\n"), out.String())
+}
+
+// failingWriter fails every write and counts them.
+type failingWriter struct{ writes int }
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return 0, assert.AnError
+}
+
+func TestDumperStopsOnWriteError(t *testing.T) {
+	w := &failingWriter{}
+	d := &dumper{w: bufio.NewWriterSize(w, 16), batchSize: 1}
+
+	assert.NoError(t, d.node(&domain.Node{ID: 0, Properties: map[string]interface{}{"name": "Ann"}}))
+	assert.ErrorIs(t, d.node(&domain.Node{ID: 1}), assert.AnError, "the first batch fails to write")
+	assert.ErrorIs(t, d.node(&domain.Node{ID: 2}), assert.AnError)
+	assert.ErrorIs(t, d.edge(&domain.Edge{Relation: "R"}), assert.AnError)
+	d.statement("RETURN 1")
+	assert.Equal(t, 1, w.writes)
+	assert.ErrorIs(t, d.err, assert.AnError)
+}
+
+func TestIndexStatements(t *testing.T) {
+	r := domain.NewRecord(
+		[]interface{}{"Person", map[string]interface{}{
+			"name": []interface{}{"RANGE", "FULLTEXT"},
+			"emb":  []interface{}{"VECTOR"},
+		}, map[string]interface{}{
+			"emb": map[string]interface{}{"dimension": int64(3), "similarityFunction": "cosine"},
+		}, "NODE"},
+		[]string{"label", "types", "options", "entitytype"},
+	)
+	assert.Equal(t, []string{
+		"CREATE VECTOR INDEX FOR (n:`Person`) ON (n.`emb`) OPTIONS {dimension: 3, similarityFunction: \"cosine\"}",
+		"CREATE INDEX FOR (n:`Person`) ON (n.`name`)",
+		"CREATE FULLTEXT INDEX FOR (n:`Person`) ON (n.`name`)",
+	}, indexStatements(r))
+
+	r = domain.NewRecord(
+		[]interface{}{"KNOWS", map[string]interface{}{"since": []interface{}{"RANGE"}}, nil, "RELATIONSHIP"},
+		[]string{"label", "types", "options", "entitytype"},
+	)
+	assert.Equal(t, []string{"CREATE INDEX FOR ()-[n:`KNOWS`]-() ON (n.`since`)"}, indexStatements(r))
+}
+
+func TestConstraintArgs(t *testing.T) {
+	r := domain.NewRecord(
+		[]interface{}{"unique", "Person", []interface{}{"name", "email"}, "NODE"},
+		[]string{"type", "label", "properties", "entitytype"},
+	)
+	assert.Equal(t, []string{"CREATE", "UNIQUE", "NODE", "Person", "PROPERTIES", "2", "name", "email"}, constraintArgs(r))
+
+	r = domain.NewRecord([]interface{}{"MANDATORY", "Person"}, []string{"type", "label"})
+	assert.Nil(t, constraintArgs(r))
+}
+
+func TestAppendDumpValue(t *testing.T) {
+	for _, tc := range []struct {
+		value interface{}
+		want  string
+	}{
+		{2.0, "2.0"},
+		{-0.5, "-0.5"},
+		{1e21, "1e21"},
+		{1e-7, "1e-07"},
+		{int64(2), "2"},
+		{"a\tb\n\"c\"\\", `"a\tb\n\"c\"\\"`},
+		{"bell\a vt\v nul\x00 del\x7f", `"bell\u0007 vt\u000b nul\u0000 del\u007f"`},
+		{"it's ü", `"it's ü"`},
+		{[]interface{}{1.0, nil, true}, "[1.0, null, true]"},
+		{map[string]interface{}{"b": 1.5, "a": "x"}, "{`a`: \"x\", `b`: 1.5}"},
+	} {
+		got, err := appendDumpValue(nil, tc.value)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.want, string(got))
+	}
+
+	_, err := appendDumpValue(nil, struct{}{})
+	assert.EqualError(t, err, "cannot dump value of type struct {}")
+	_, err = appendDumpValue(nil, math.NaN())
+	assert.Error(t, err)
+
+	var out strings.Builder
+	d := &dumper{w: bufio.NewWriter(&out), batchSize: 2}
+	assert.NoError(t, d.node(&domain.Node{ID: 0, Properties: map[string]interface{}{"bad": []int{1}}}))
+	assert.EqualError(t, d.flushNodes(), "node 0: property bad: cannot dump value of type []int")
+}
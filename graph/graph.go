@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/redis/go-redis/v9"
 
@@ -19,9 +20,13 @@ const (
 
 var ctx = context.Background()
 
+// ErrReadOnly is returned when a write is attempted on a read-only graph.
+var ErrReadOnly = errors.New("graph is read-only")
+
 // QueryOptions are a set of additional arguments to be emitted with a query.
 type QueryOptions struct {
-	timeout int
+	timeout    int
+	idempotent bool
 }
 
 // QueryRequest represents a single graph command to enqueue in a pipeline.
@@ -32,12 +37,26 @@ type QueryRequest struct {
 	Options *QueryOptions
 }
 
+// ReadRouter chooses the client that read only queries are sent on, such as
+// a replica of the graph's master.
+type ReadRouter interface {
+	// ReadClient returns the client for a read only query on key, or nil to
+	// send it on the graph's own connection.
+	ReadClient(ctx context.Context, key string) (redis.UniversalClient, error)
+}
+
 // Graph represents a graph, which is a collection of nodes and edges.
 type Graph struct {
 	Id       string
 	Conn     redis.UniversalClient
 	schema   GraphSchema
+	schemaMu sync.Mutex // guards schema, which results parsed concurrently refresh
 	readonly bool
+	reads    ReadRouter
+	defaults Defaults
+	hooks    []QueryHook
+	tracker  Tracker
+	caps     *CapabilityCache
 }
 
 // New creates a new graph.
@@ -52,6 +71,7 @@ func NewWithMode(Id string, conn redis.UniversalClient, readonly bool) *Graph {
 	g.Conn = conn
 	g.readonly = readonly
 	g.schema = GraphSchemaNew(g)
+	g.caps = NewCapabilityCache(conn)
 	return g
 }
 
@@ -60,6 +80,20 @@ func NewGraphWithSchema(schema GraphSchema) *Graph {
 	return &Graph{schema: schema}
 }
 
+// ReadOnly reports whether the graph only allows read only queries, because
+// it was created read-only or its defaults prefer it.
+func (g *Graph) ReadOnly() bool {
+	return g.readonly || g.defaults.ReadOnly
+}
+
+// SetReadRouter routes ROQuery and ROQueryContext through r. Writes, streams
+// and pipelines always use Conn. A read that fails because the chosen client
+// is unreachable or not serving the graph is retried on Conn.
+func (g *Graph) SetReadRouter(r ReadRouter) *Graph {
+	g.reads = r
+	return g
+}
+
 // ExecutionPlan gets the execution plan for given query.
 func (g *Graph) ExecutionPlan(query string) (string, error) {
 	return g.Conn.Do(ctx, "GRAPH.EXPLAIN", g.Id, query).Text()
@@ -68,7 +102,11 @@ func (g *Graph) ExecutionPlan(query string) (string, error) {
 // Profile executes a query and returns an execution plan augmented with metrics.
 func (g *Graph) Profile(query string, params map[string]interface{}, options *QueryOptions) ([]string, error) {
 	if params != nil {
-		query = BuildParamsHeader(params) + query
+		header, err := paramsHeader(params)
+		if err != nil {
+			return nil, err
+		}
+		query = header + query
 	}
 
 	args := []interface{}{g.Id, query, "--compact"}
@@ -107,7 +145,9 @@ func (g *Graph) Delete() error {
 	err := g.Conn.Do(ctx, "GRAPH.DELETE", g.Id).Err()
 
 	// clear internal mappings
+	g.schemaMu.Lock()
 	g.schema.clear()
+	g.schemaMu.Unlock()
 
 	return err
 }
@@ -125,26 +165,86 @@ func (options *QueryOptions) SetTimeout(timeout int) *QueryOptions {
 	return options
 }
 
+// Merge returns new options holding the settings of other, and those of
+// options where other leaves them unset. Either may be nil. Graphs merge the
+// client defaults, then the graph defaults, then the options of the query,
+// so the most specific setting wins.
+func (options *QueryOptions) Merge(other *QueryOptions) *QueryOptions {
+	merged := NewQueryOptions()
+	if options != nil {
+		*merged = *options
+	}
+	if other != nil {
+		if other.timeout >= 0 {
+			merged.timeout = other.timeout
+		}
+		merged.idempotent = merged.idempotent || other.idempotent
+	}
+	return merged
+}
+
+// SetIdempotent marks a query as safe to run more than once, which allows a
+// RetryPolicy to retry it although it is not read only. Once set by the
+// client or graph defaults it cannot be cleared per query.
+func (options *QueryOptions) SetIdempotent(idempotent bool) *QueryOptions {
+	options.idempotent = idempotent
+	return options
+}
+
+// Idempotent reports whether the query is marked idempotent.
+func (options *QueryOptions) Idempotent() bool {
+	return options != nil && options.idempotent
+}
+
 // GetTimeout retrieves the timeout of the QueryOptions struct
 func (options *QueryOptions) GetTimeout() int {
 	return options.timeout
 }
 
-func (g *Graph) query(command string, query string, params map[string]interface{}, options *QueryOptions) (*QueryResult, error) {
-	if g.readonly && command != CmdROQuery {
-		return nil, errors.New("graph is read-only")
-	}
+// commandArgs builds the full argument list of a query command.
+func (g *Graph) commandArgs(command string, query string, params map[string]interface{}, options *QueryOptions) ([]interface{}, error) {
 	if params != nil {
-		query = BuildParamsHeader(params) + query
+		header, err := paramsHeader(params)
+		if err != nil {
+			return nil, err
+		}
+		query = header + query
 	}
 
-	args := []interface{}{g.Id, query, "--compact"}
+	options = g.defaults.Options.Merge(options)
+
+	args := []interface{}{command, g.Id, query, "--compact"}
 	if options != nil && options.timeout >= 0 {
 		args = append(args, "timeout", options.timeout)
 	}
+	return args, nil
+}
+
+func (g *Graph) query(ctx context.Context, command string, query string, params map[string]interface{}, options *QueryOptions) (*QueryResult, error) {
+	if g.ReadOnly() && command != CmdROQuery {
+		return nil, ErrReadOnly
+	}
+
+	return g.run(ctx, QueryRequest{Command: command, Query: query, Params: params, Options: options})
+}
 
-	cmdArgs := append([]interface{}{command}, args...)
-	r, err := g.Conn.Do(ctx, cmdArgs...).Result()
+// exec sends a single request, routing reads through the read router.
+func (g *Graph) exec(ctx context.Context, req QueryRequest) (*QueryResult, error) {
+	args, err := g.commandArgs(req.Command, req.Query, req.Params, req.Options)
+	if err != nil {
+		return nil, err
+	}
+	conn := g.Conn
+	if req.Command == CmdROQuery && g.reads != nil {
+		if c, err := g.reads.ReadClient(ctx, g.Id); err == nil && c != nil {
+			conn = c
+		}
+	}
+
+	r, err := conn.Do(ctx, args...).Result()
+	if err != nil && conn != g.Conn && ctx.Err() == nil && isUnavailable(err) {
+		r, err = g.Conn.Do(ctx, args...).Result()
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -152,37 +252,49 @@ func (g *Graph) query(command string, query string, params map[string]interface{
 	return QueryResultNew(g, r)
 }
 
+// isUnavailable reports whether err means the server could not serve the
+// request at all, as opposed to the request failing.
+func isUnavailable(err error) bool {
+	var rerr redis.Error
+	if !errors.As(err, &rerr) {
+		return true
+	}
+	for _, prefix := range []string{"MOVED ", "ASK ", "LOADING", "MASTERDOWN", "TRYAGAIN", "CLUSTERDOWN"} {
+		if strings.HasPrefix(err.Error(), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // Pipeline executes multiple graph commands in a single round-trip and returns results in order.
 // Each request can target GRAPH.QUERY or GRAPH.RO_QUERY via the Command field (defaults to GRAPH.QUERY).
 func (g *Graph) Pipeline(reqs []QueryRequest) ([]*QueryResult, error) {
 	if len(reqs) == 0 {
 		return nil, nil
 	}
+	if _, err := g.enter(); err != nil {
+		return nil, err
+	}
+	defer g.leave()
 
 	pipe := g.Conn.Pipeline()
 	cmds := make([]*redis.Cmd, len(reqs))
 
 	for i, req := range reqs {
-		q := req.Query
-		if req.Params != nil {
-			q = BuildParamsHeader(req.Params) + q
-		}
-
-		args := []interface{}{g.Id, q, "--compact"}
-		if req.Options != nil && req.Options.timeout >= 0 {
-			args = append(args, "timeout", req.Options.timeout)
-		}
-
 		command := req.Command
 		if command == "" {
 			command = CmdQuery
 		}
-		if g.readonly && command != CmdROQuery {
-			return nil, errors.New("graph is read-only")
+		if g.ReadOnly() && command != CmdROQuery {
+			return nil, ErrReadOnly
 		}
 
-		cmdArgs := append([]interface{}{command}, args...)
-		cmds[i] = pipe.Do(ctx, cmdArgs...)
+		args, err := g.commandArgs(command, req.Query, req.Params, req.Options)
+		if err != nil {
+			return nil, err
+		}
+		cmds[i] = pipe.Do(ctx, args...)
 	}
 
 	if _, err := pipe.Exec(ctx); err != nil {
@@ -207,12 +319,22 @@ func (g *Graph) Pipeline(reqs []QueryRequest) ([]*QueryResult, error) {
 
 // Query executes a query against the graph.
 func (g *Graph) Query(query string, params map[string]interface{}, options *QueryOptions) (*QueryResult, error) {
-	return g.query(CmdQuery, query, params, options)
+	return g.query(ctx, CmdQuery, query, params, options)
 }
 
 // ROQuery executes a read only query against the graph.
 func (g *Graph) ROQuery(query string, params map[string]interface{}, options *QueryOptions) (*QueryResult, error) {
-	return g.query(CmdROQuery, query, params, options)
+	return g.query(ctx, CmdROQuery, query, params, options)
+}
+
+// QueryContext executes a query against the graph using ctx.
+func (g *Graph) QueryContext(ctx context.Context, query string, params map[string]interface{}, options *QueryOptions) (*QueryResult, error) {
+	return g.query(ctx, CmdQuery, query, params, options)
+}
+
+// ROQueryContext executes a read only query against the graph using ctx.
+func (g *Graph) ROQueryContext(ctx context.Context, query string, params map[string]interface{}, options *QueryOptions) (*QueryResult, error) {
+	return g.query(ctx, CmdROQuery, query, params, options)
 }
 
 // Procedures
@@ -220,6 +342,10 @@ func (g *Graph) ROQuery(query string, params map[string]interface{}, options *Qu
 // MemoryUsage returns detailed memory consumption statistics for a specific graph.
 // samples: Number of samples to take when estimating memory usage. (default 100 if -1)
 func (g *Graph) MemoryUsage(samples int) (map[string]interface{}, error) {
+	if err := g.caps.Require(ctx, FeatureMemoryUsage); err != nil {
+		return nil, err
+	}
+
 	args := []interface{}{"GRAPH.MEMORY", "USAGE", g.Id}
 	if samples > 0 {
 		args = append(args, "SAMPLES", samples)
@@ -280,11 +406,34 @@ func (g *Graph) CallProcedure(procedure string, yield []string, args ...interfac
 	return g.Query(query, nil, nil)
 }
 
-// BuildParamsHeader builds a CYPHER params header from key/value pairs.
+// BuildParamsHeader builds a CYPHER params header from key/value pairs. It
+// panics on values that have no Cypher encoding.
 func BuildParamsHeader(params map[string]interface{}) string {
-	header := "CYPHER "
-	for key, value := range params {
-		header += fmt.Sprintf("%s=%v ", key, strs.ToString(value))
+	header, err := paramsHeader(params)
+	if err != nil {
+		panic(err)
 	}
 	return header
 }
+
+// paramsHeader builds a CYPHER params header, or returns an error for values
+// that have no Cypher encoding, such as NaN.
+func paramsHeader(params map[string]interface{}) (string, error) {
+	header := make([]byte, 0, 64)
+	header = append(header, "CYPHER "...)
+	for key, value := range params {
+		header = append(header, key...)
+		header = append(header, '=')
+		var err error
+		if header, err = strs.AppendValue(header, value); err != nil {
+			return "", fmt.Errorf("param %s: %w", key, err)
+		}
+		header = append(header, ' ')
+	}
+	return string(header), nil
+}
+
+// QuoteIdentifier escapes a label, relationship type or property name for use in a query.
+func QuoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
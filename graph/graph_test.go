@@ -1,8 +1,12 @@
 package graph
 
 import (
+	"errors"
+	"math"
+	"strings"
 	"testing"
 
+	"github.com/snowmerak/falkordb-go/domain"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -53,3 +57,65 @@ func TestParseProfileResponse(t *testing.T) {
 		})
 	}
 }
+
+func embedding(dim int) domain.Vector {
+	v := make(domain.Vector, dim)
+	for i := range v {
+		v[i] = float32(i)*0.001 - 0.7
+	}
+	return v
+}
+
+func TestBuildParamsHeaderVector(t *testing.T) {
+	header := BuildParamsHeader(map[string]interface{}{"v": domain.Vector{1, 0.5}})
+	assert.Equal(t, "CYPHER v=vecf32([1,0.5]) ", header)
+}
+
+func TestQueryRejectsNonFiniteParams(t *testing.T) {
+	g := hookedGraph(t, func(args []interface{}) (interface{}, error) {
+		t.Errorf("sent %v", args)
+		return nil, nil
+	})
+	for _, v := range []interface{}{math.NaN(), math.Inf(1), domain.Vector{1, float32(math.Inf(-1))}} {
+		_, err := g.Query("RETURN $v", map[string]interface{}{"v": v}, nil)
+		assert.ErrorContains(t, err, "param v: float")
+		assert.ErrorContains(t, err, "has no Cypher literal")
+	}
+	assert.Panics(t, func() { BuildParamsHeader(map[string]interface{}{"v": math.NaN()}) })
+}
+
+func TestBuildParamsHeaderAllocations(t *testing.T) {
+	small := map[string]interface{}{"v": embedding(1536)}
+	large := map[string]interface{}{"v": embedding(4 * 1536)}
+
+	smallAllocs := testing.AllocsPerRun(10, func() { BuildParamsHeader(small) })
+	largeAllocs := testing.AllocsPerRun(10, func() { BuildParamsHeader(large) })
+
+	// Allocation count grows with the logarithm of the header size (buffer
+	// doubling), never with the number of elements.
+	assert.Less(t, largeAllocs, smallAllocs+5)
+	assert.True(t, strings.HasPrefix(BuildParamsHeader(small), "CYPHER v=vecf32(["))
+}
+
+func BenchmarkBuildParamsHeaderVector1536(b *testing.B) {
+	params := map[string]interface{}{"embedding": embedding(1536)}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		BuildParamsHeader(params)
+	}
+}
+
+type testRedisError string
+
+func (e testRedisError) Error() string { return string(e) }
+
+func (testRedisError) RedisError() {}
+
+func TestIsUnavailable(t *testing.T) {
+	assert.True(t, isUnavailable(errors.New("dial tcp: connection refused")))
+	assert.True(t, isUnavailable(testRedisError("MOVED 3999 127.0.0.1:6381")))
+	assert.True(t, isUnavailable(testRedisError("LOADING Redis is loading the dataset in memory")))
+	assert.True(t, isUnavailable(testRedisError("MASTERDOWN Link with MASTER is down")))
+	assert.False(t, isUnavailable(testRedisError("Query timed out")))
+	assert.False(t, isUnavailable(testRedisError("errMsg: Invalid input")))
+}
@@ -0,0 +1,72 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrModuleNotLoaded is returned when the server does not have the FalkorDB
+// module loaded.
+var ErrModuleNotLoaded = errors.New("graph module not loaded")
+
+// ModuleVersion returns the version of the FalkorDB module, such as 41000
+// for 4.10.0, on the server holding the graph key.
+func (g *Graph) ModuleVersion(ctx context.Context) (int, error) {
+	var conn redis.UniversalClient = g.Conn
+	if c, err := g.streamClient(ctx); err == nil {
+		conn = c
+	}
+	return ServerModuleVersion(ctx, conn)
+}
+
+// ServerModuleVersion returns the version of the FalkorDB module on the
+// server conn sends commands to, or ErrModuleNotLoaded.
+func ServerModuleVersion(ctx context.Context, conn redis.UniversalClient) (int, error) {
+	res, err := conn.Do(ctx, "MODULE", "LIST").Result()
+	if err != nil {
+		return 0, err
+	}
+	return parseModuleVersion(res)
+}
+
+// parseModuleVersion finds the graph module in a MODULE LIST reply, which
+// holds either maps (RESP3) or flat name/value arrays (RESP2).
+func parseModuleVersion(res interface{}) (int, error) {
+	modules, ok := res.([]interface{})
+	if !ok {
+		return 0, fmt.Errorf("unexpected module list type %T", res)
+	}
+
+	for _, m := range modules {
+		fields := make(map[string]interface{})
+		switch entry := m.(type) {
+		case map[interface{}]interface{}:
+			for k, v := range entry {
+				if key, ok := k.(string); ok {
+					fields[key] = v
+				}
+			}
+		case []interface{}:
+			for i := 0; i+1 < len(entry); i += 2 {
+				if key, ok := entry[i].(string); ok {
+					fields[key] = entry[i+1]
+				}
+			}
+		default:
+			return 0, fmt.Errorf("unexpected module entry type %T", m)
+		}
+
+		if fields["name"] != "graph" {
+			continue
+		}
+		ver, ok := fields["ver"].(int64)
+		if !ok {
+			return 0, fmt.Errorf("unexpected module version type %T", fields["ver"])
+		}
+		return int(ver), nil
+	}
+	return 0, ErrModuleNotLoaded
+}
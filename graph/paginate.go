@@ -0,0 +1,348 @@
+package graph
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/snowmerak/falkordb-go/domain"
+)
+
+const (
+	pageKeyColumn = "__falkordb_page_key"
+	pageAfterKey  = "__falkordb_page_after"
+	pageSizeKey   = "__falkordb_page_size"
+)
+
+// ErrNoMorePages is returned by Cursor.Next once every page has been read.
+var ErrNoMorePages = errors.New("no more pages")
+
+// PageSpec describes how Paginate splits a query into pages.
+type PageSpec struct {
+	// OrderBy is an expression over the query's variables that yields a
+	// unique, non-null integer, float or string per row, such as "n.uuid"
+	// or "id(n)". Rows are returned in the order of this key.
+	OrderBy string
+	// PageSize is the maximum number of records per page.
+	PageSize int
+	// Descending returns rows in descending key order.
+	Descending bool
+	// Token resumes pagination after the page a previous cursor returned last.
+	Token string
+}
+
+// Cursor walks the pages of a paginated query.
+type Cursor struct {
+	graph       *Graph
+	params      map[string]interface{}
+	spec        PageSpec
+	first       string
+	query       string
+	fingerprint string
+
+	last    interface{}
+	started bool
+	done    bool
+}
+
+type pageToken struct {
+	Fingerprint string `json:"f"`
+	Type        string `json:"t"`
+	Value       string `json:"v"`
+}
+
+// Paginate prepares a keyset paginated read of query. The query is rewritten
+// so that every page filters on the OrderBy key of the previous page instead
+// of using SKIP, which keeps the cost of a page independent of its position.
+// When RETURN directly follows a MATCH the filter is added to the MATCH's
+// WHERE, where an index on the OrderBy property can serve it.
+// The query's final RETURN clause must not contain ORDER BY, SKIP or LIMIT,
+// nor aggregate across rows. No query is run until Cursor.Next.
+func (g *Graph) Paginate(ctx context.Context, query string, params map[string]interface{}, spec PageSpec) (*Cursor, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if spec.PageSize <= 0 {
+		return nil, errors.New("page size must be positive")
+	}
+	if strings.TrimSpace(spec.OrderBy) == "" {
+		return nil, errors.New("page order by expression is required")
+	}
+
+	first, next, err := rewritePageQuery(query, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Cursor{
+		graph:       g,
+		params:      params,
+		spec:        spec,
+		first:       first,
+		query:       next,
+		fingerprint: pageFingerprint(query, spec),
+	}
+
+	if spec.Token != "" {
+		last, err := c.decodeToken(spec.Token)
+		if err != nil {
+			return nil, err
+		}
+		c.last = last
+		c.started = true
+	}
+	return c, nil
+}
+
+// Next reads the next page. It returns ErrNoMorePages after the last page.
+// The returned result does not contain the internal page key column.
+func (c *Cursor) Next(ctx context.Context) (*QueryResult, error) {
+	if c.done {
+		return nil, ErrNoMorePages
+	}
+
+	params := make(map[string]interface{}, len(c.params)+2)
+	for k, v := range c.params {
+		params[k] = v
+	}
+	// one row more than a page tells whether another page follows
+	params[pageSizeKey] = c.spec.PageSize + 1
+	query := c.first
+	if c.started {
+		query = c.query
+		params[pageAfterKey] = c.last
+	}
+
+	qr, err := c.graph.query(ctx, CmdROQuery, query, params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	keyIdx := len(qr.header.column_names) - 1
+	if keyIdx < 0 || qr.header.column_names[keyIdx] != pageKeyColumn {
+		return nil, errors.New("page key column missing from result")
+	}
+
+	more := len(qr.results) > c.spec.PageSize
+	if more {
+		qr.results = qr.results[:c.spec.PageSize]
+	}
+	for i, r := range qr.results {
+		key := r.GetByIndex(keyIdx)
+		switch key.(type) {
+		case int64, float64, string:
+		default:
+			return nil, fmt.Errorf("unsupported page key type %T", key)
+		}
+		if i == len(qr.results)-1 {
+			c.last = key
+			c.started = true
+		}
+		values := r.Values()
+		qr.results[i] = domain.NewRecord(values[:keyIdx], qr.header.column_names[:keyIdx])
+	}
+	qr.header.column_names = qr.header.column_names[:keyIdx]
+	qr.header.column_types = qr.header.column_types[:keyIdx]
+
+	c.done = !more
+	return qr, nil
+}
+
+// Done reports whether the last page has been read.
+func (c *Cursor) Done() bool {
+	return c.done
+}
+
+// Token returns an opaque continuation token for the position after the last
+// page read, to be passed as PageSpec.Token. It is empty before the first page.
+func (c *Cursor) Token() string {
+	if !c.started {
+		return ""
+	}
+	t := pageToken{Fingerprint: c.fingerprint}
+	switch v := c.last.(type) {
+	case int64:
+		t.Type = "int"
+		t.Value = strconv.FormatInt(v, 10)
+	case float64:
+		t.Type = "float"
+		t.Value = strconv.FormatFloat(v, 'g', -1, 64)
+	case string:
+		t.Type = "string"
+		t.Value = v
+	}
+	b, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func (c *Cursor) decodeToken(token string) (interface{}, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid page token: %w", err)
+	}
+	var t pageToken
+	if err := json.Unmarshal(raw, &t); err != nil {
+		return nil, fmt.Errorf("invalid page token: %w", err)
+	}
+	if t.Fingerprint != c.fingerprint {
+		return nil, errors.New("page token does not belong to this query")
+	}
+	switch t.Type {
+	case "int":
+		return strconv.ParseInt(t.Value, 10, 64)
+	case "float":
+		return strconv.ParseFloat(t.Value, 64)
+	case "string":
+		return t.Value, nil
+	}
+	return nil, fmt.Errorf("invalid page token type %q", t.Type)
+}
+
+func pageFingerprint(query string, spec PageSpec) string {
+	h := fnv.New64a()
+	h.Write([]byte(query))
+	h.Write([]byte{0})
+	h.Write([]byte(spec.OrderBy))
+	if spec.Descending {
+		h.Write([]byte{1})
+	}
+	return strconv.FormatUint(h.Sum64(), 36)
+}
+
+// pageClauses are the keywords starting a clause that can precede RETURN.
+var pageClauses = map[string]bool{
+	"MATCH": true, "WITH": true, "UNWIND": true, "CALL": true, "YIELD": true,
+	"CREATE": true, "MERGE": true, "SET": true, "DELETE": true, "REMOVE": true, "FOREACH": true,
+}
+
+// rewritePageQuery returns the queries of the first and of the following
+// pages, which filter on the page key and order and limit the projection by
+// it. The filter goes into the WHERE of a MATCH directly before RETURN, and
+// into a WITH * projecting the key otherwise.
+func rewritePageQuery(query string, spec PageSpec) (string, string, error) {
+	words := topLevelWords(query)
+
+	ret := -1
+	for i, w := range words {
+		switch w.word {
+		case "UNION":
+			return "", "", errors.New("paginated query must not contain UNION")
+		case "RETURN":
+			ret = i
+		}
+	}
+	if ret < 0 {
+		return "", "", errors.New("paginated query must end with a RETURN clause")
+	}
+	for _, w := range words[ret+1:] {
+		switch w.word {
+		case "ORDER", "SKIP", "LIMIT":
+			return "", "", fmt.Errorf("paginated query must not contain %s after RETURN", w.word)
+		}
+	}
+
+	cmp, dir := ">", ""
+	if spec.Descending {
+		cmp, dir = "<", " DESC"
+	}
+
+	head := strings.TrimRight(query[:words[ret].pos], " \t\r\n")
+	projection := strings.TrimRight(strings.TrimSpace(query[words[ret].pos+len("RETURN"):]), ";")
+	projection = strings.TrimSpace(projection)
+	tail := fmt.Sprintf(" RETURN %s, %s AS %s ORDER BY %s%s LIMIT $%s",
+		projection, spec.OrderBy, pageKeyColumn, pageKeyColumn, dir, pageSizeKey)
+
+	firstFilter := spec.OrderBy + " IS NOT NULL"
+	nextFilter := fmt.Sprintf("%s %s $%s", spec.OrderBy, cmp, pageAfterKey)
+
+	clause := -1
+	for i := ret - 1; i >= 0; i-- {
+		if pageClauses[words[i].word] {
+			clause = i
+			break
+		}
+	}
+	if clause < 0 || words[clause].word != "MATCH" || clause > 0 && words[clause-1].word == "OPTIONAL" {
+		// the key is not in scope of a MATCH's WHERE, filter on a projection
+		with := fmt.Sprintf("%s WITH * WHERE ", head)
+		return with + firstFilter + tail, with + nextFilter + tail, nil
+	}
+
+	where := -1
+	for i := clause + 1; i < ret; i++ {
+		if words[i].word == "WHERE" {
+			where = i
+			break
+		}
+	}
+	if where < 0 {
+		return head + " WHERE " + firstFilter + tail, head + " WHERE " + nextFilter + tail, nil
+	}
+	cond := strings.TrimSpace(head[words[where].pos+len("WHERE"):])
+	prefix := head[:words[where].pos] + "WHERE (" + cond + ") AND "
+	return prefix + firstFilter + tail, prefix + nextFilter + tail, nil
+}
+
+type queryWord struct {
+	word string
+	pos  int
+}
+
+// topLevelWords returns the upper-cased keywords of query that appear outside
+// of string literals, quoted identifiers, comments and brackets.
+func topLevelWords(query string) []queryWord {
+	var words []queryWord
+	depth := 0
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			i++
+			for i < len(query) && query[i] != c {
+				if query[i] == '\\' && c != '`' {
+					i++
+				}
+				i++
+			}
+			i++
+		case c == '/' && i+1 < len(query) && query[i+1] == '/':
+			for i < len(query) && query[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(query) && query[i+1] == '*':
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				i = len(query)
+			} else {
+				i += end + 4
+			}
+		case c == '(' || c == '[' || c == '{':
+			depth++
+			i++
+		case c == ')' || c == ']' || c == '}':
+			depth--
+			i++
+		case isWordByte(c):
+			start := i
+			for i < len(query) && isWordByte(query[i]) {
+				i++
+			}
+			if depth == 0 && (start == 0 || query[start-1] != '.' && query[start-1] != '$') {
+				words = append(words, queryWord{word: strings.ToUpper(query[start:i]), pos: start})
+			}
+		default:
+			i++
+		}
+	}
+	return words
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c < 0x80 && (unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c)))
+}
@@ -0,0 +1,119 @@
+package graph
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewritePageQuery(t *testing.T) {
+	first, next, err := rewritePageQuery("MATCH (n:Person) WHERE n.age > $age OR n.vip RETURN n.name, n;", PageSpec{OrderBy: "n.uuid", PageSize: 10})
+	require.NoError(t, err)
+	assert.Equal(t,
+		"MATCH (n:Person) WHERE (n.age > $age OR n.vip) AND n.uuid IS NOT NULL"+
+			" RETURN n.name, n, n.uuid AS __falkordb_page_key ORDER BY __falkordb_page_key LIMIT $__falkordb_page_size", first)
+	assert.Equal(t,
+		"MATCH (n:Person) WHERE (n.age > $age OR n.vip) AND n.uuid > $__falkordb_page_after"+
+			" RETURN n.name, n, n.uuid AS __falkordb_page_key ORDER BY __falkordb_page_key LIMIT $__falkordb_page_size", next)
+
+	first, next, err = rewritePageQuery("MATCH (n) RETURN n", PageSpec{OrderBy: "n.uuid", PageSize: 10, Descending: true})
+	require.NoError(t, err)
+	assert.Equal(t, "MATCH (n) WHERE n.uuid IS NOT NULL RETURN n, n.uuid AS __falkordb_page_key ORDER BY __falkordb_page_key DESC LIMIT $__falkordb_page_size", first)
+	assert.Contains(t, next, "MATCH (n) WHERE n.uuid < $__falkordb_page_after RETURN")
+}
+
+func TestRewritePageQueryProjectsAfterOtherClauses(t *testing.T) {
+	first, next, err := rewritePageQuery(
+		"MATCH (n) WHERE n.name <> 'RETURN x LIMIT 1' CALL { WITH n MATCH (n)-->(m) RETURN count(m) AS c } RETURN n, c // ORDER BY n",
+		PageSpec{OrderBy: "id(n)", PageSize: 5})
+	require.NoError(t, err)
+	assert.Contains(t, first, "RETURN count(m) AS c } WITH * WHERE id(n) IS NOT NULL RETURN n, c")
+	assert.Contains(t, next, "RETURN count(m) AS c } WITH * WHERE id(n) > $__falkordb_page_after RETURN n, c")
+
+	_, next, err = rewritePageQuery("MATCH (n) OPTIONAL MATCH (n)-->(m) RETURN n, m", PageSpec{OrderBy: "id(n)", PageSize: 5})
+	require.NoError(t, err)
+	assert.Contains(t, next, "OPTIONAL MATCH (n)-->(m) WITH * WHERE id(n) > $__falkordb_page_after RETURN")
+}
+
+func TestRewritePageQueryRejects(t *testing.T) {
+	for _, query := range []string{
+		"MATCH (n) RETURN n ORDER BY n.name",
+		"MATCH (n) RETURN n SKIP 10",
+		"MATCH (n) RETURN n LIMIT 10",
+		"MATCH (n:A) RETURN n UNION MATCH (n:B) RETURN n",
+		"CREATE (n)",
+	} {
+		_, _, err := rewritePageQuery(query, PageSpec{OrderBy: "id(n)", PageSize: 10})
+		assert.Error(t, err, query)
+	}
+}
+
+func TestPageToken(t *testing.T) {
+	g := NewGraphWithSchema(GraphSchemaWithData(nil, nil, nil))
+	spec := PageSpec{OrderBy: "n.uuid", PageSize: 10}
+
+	for _, last := range []interface{}{int64(42), 1.5, "abc"} {
+		c, err := g.Paginate(context.Background(), "MATCH (n) RETURN n", nil, spec)
+		require.NoError(t, err)
+		assert.Empty(t, c.Token())
+
+		c.last, c.started = last, true
+		resumed := spec
+		resumed.Token = c.Token()
+		r, err := g.Paginate(context.Background(), "MATCH (n) RETURN n", nil, resumed)
+		require.NoError(t, err)
+		assert.Equal(t, last, r.last)
+		assert.True(t, r.started)
+
+		_, err = g.Paginate(context.Background(), "MATCH (m) RETURN m", nil, resumed)
+		assert.EqualError(t, err, "page token does not belong to this query")
+	}
+
+	_, err := g.Paginate(context.Background(), "MATCH (n) RETURN n", nil, PageSpec{OrderBy: "n.uuid", PageSize: 10, Token: "!"})
+	assert.ErrorContains(t, err, "invalid page token")
+}
+
+func TestCursorLastFullPage(t *testing.T) {
+	var sizes []string
+	g := hookedGraph(t, func(args []interface{}) (interface{}, error) {
+		query := args[2].(string)
+		sizes = append(sizes, regexp.MustCompile(pageSizeKey + `=(\d+)`).FindStringSubmatch(query)[1])
+		after := int64(0)
+		if m := regexp.MustCompile(pageAfterKey + `=(\d+)`).FindStringSubmatch(query); m != nil {
+			after, _ = strconv.ParseInt(m[1], 10, 64)
+		}
+		var rows []interface{}
+		for k := after + 1; k <= 4 && len(rows) < 3; k++ {
+			cell := []interface{}{int64(VALUE_INTEGER), k}
+			rows = append(rows, []interface{}{cell, cell})
+		}
+		return []interface{}{
+			[]interface{}{[]interface{}{int64(COLUMN_SCALAR), "k"}, []interface{}{int64(COLUMN_SCALAR), pageKeyColumn}},
+			rows,
+			[]interface{}{},
+		}, nil
+	})
+
+	c, err := g.Paginate(context.Background(), "MATCH (n) RETURN n.k AS k", nil, PageSpec{OrderBy: "n.k", PageSize: 2})
+	require.NoError(t, err)
+
+	page, err := c.Next(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, page.Results(), 2)
+	assert.False(t, c.Done())
+
+	// the second page is full, but no row follows it
+	page, err = c.Next(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, page.Results(), 2)
+	assert.Equal(t, int64(4), page.Results()[1].GetByIndex(0))
+	assert.True(t, c.Done())
+
+	_, err = c.Next(context.Background())
+	assert.ErrorIs(t, err, ErrNoMorePages)
+	assert.Equal(t, []string{"3", "3"}, sizes)
+}
@@ -0,0 +1,196 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrNotSent is reported for requests of a pipeline that were not sent
+// because an earlier batch failed.
+var ErrNotSent = errors.New("request not sent")
+
+// PipelineOptions configures PipelineWithOptions.
+type PipelineOptions struct {
+	// BatchSize is the maximum number of requests per round trip.
+	// Zero sends all requests in one batch.
+	BatchSize int
+	// BatchBytes is the maximum total query size of a batch. A single larger
+	// request is sent as a batch of its own. Zero means no limit.
+	BatchBytes int
+	// Concurrency is the number of batches in flight at once (default 1).
+	Concurrency int
+	// ContinueOnError sends all batches even after a request failed.
+	// By default no further batches are started after a failure and the
+	// remaining requests report ErrNotSent.
+	ContinueOnError bool
+	// ValidateReadOnly rejects the whole pipeline with ErrReadOnly before
+	// anything is sent when the graph is read-only and any request is a
+	// write. Otherwise only those requests fail with ErrReadOnly.
+	ValidateReadOnly bool
+}
+
+// PipelineResult holds the outcome of every request of a pipeline.
+type PipelineResult struct {
+	// Results holds the result of each request, or nil where it failed.
+	Results []*QueryResult
+	// Errors holds the error of each request, or nil where it succeeded.
+	Errors []error
+}
+
+// Err returns the first request error, if any.
+func (r *PipelineResult) Err() error {
+	for i, err := range r.Errors {
+		if err != nil {
+			return fmt.Errorf("request %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// PipelineWithOptions executes requests in pipelined batches and reports a
+// result or an error for each request, in order. The returned error is the
+// first request error, as returned by PipelineResult.Err.
+func (g *Graph) PipelineWithOptions(ctx context.Context, reqs []QueryRequest, opts *PipelineOptions) (*PipelineResult, error) {
+	if _, err := g.enter(); err != nil {
+		return nil, err
+	}
+	defer g.leave()
+
+	var o PipelineOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = 1
+	}
+
+	res := &PipelineResult{
+		Results: make([]*QueryResult, len(reqs)),
+		Errors:  make([]error, len(reqs)),
+	}
+
+	args := make([][]interface{}, len(reqs))
+	for i, req := range reqs {
+		a, err := g.PipelineArgs(req)
+		if err != nil {
+			if o.ValidateReadOnly {
+				return nil, err
+			}
+			res.Errors[i] = err
+			continue
+		}
+		args[i] = a
+	}
+
+	batches := splitPipeline(args, o.BatchSize, o.BatchBytes)
+
+	var failed atomic.Bool
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, o.Concurrency)
+	for bi, b := range batches {
+		sem <- struct{}{}
+		if failed.Load() && !o.ContinueOnError {
+			<-sem
+			for _, rest := range batches[bi:] {
+				for i := rest[0]; i < rest[1]; i++ {
+					if args[i] != nil {
+						res.Errors[i] = ErrNotSent
+					}
+				}
+			}
+			break
+		}
+
+		wg.Add(1)
+		go func(start, end int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if g.execPipelineBatch(ctx, args[start:end], res.Results[start:end], res.Errors[start:end]) {
+				failed.Store(true)
+			}
+		}(b[0], b[1])
+	}
+	wg.Wait()
+
+	return res, res.Err()
+}
+
+// PipelineArgs returns the command arguments of req, for sending it on a
+// pipeline managed by the caller. Parse the reply with QueryResultNew.
+// Writes on a read-only graph fail with ErrReadOnly.
+func (g *Graph) PipelineArgs(req QueryRequest) ([]interface{}, error) {
+	command := req.Command
+	if command == "" {
+		command = CmdQuery
+	}
+	if g.ReadOnly() && command != CmdROQuery {
+		return nil, ErrReadOnly
+	}
+	return g.commandArgs(command, req.Query, req.Params, req.Options)
+}
+
+// splitPipeline splits requests into [start, end) batches. Requests without
+// arguments are not sent but still belong to a batch to keep indexes stable.
+func splitPipeline(args [][]interface{}, size, bytes int) [][2]int {
+	var batches [][2]int
+	start, count, total := 0, 0, 0
+	for i, a := range args {
+		n := 0
+		if a != nil {
+			n = len(a[2].(string))
+		}
+		if count > 0 && ((size > 0 && count >= size) || (bytes > 0 && total+n > bytes)) {
+			batches = append(batches, [2]int{start, i})
+			start, count, total = i, 0, 0
+		}
+		if a != nil {
+			count++
+			total += n
+		}
+	}
+	if start < len(args) {
+		batches = append(batches, [2]int{start, len(args)})
+	}
+	return batches
+}
+
+// execPipelineBatch sends one batch and reports whether any request failed.
+func (g *Graph) execPipelineBatch(ctx context.Context, args [][]interface{}, results []*QueryResult, errs []error) bool {
+	cmds := make([]*redis.Cmd, len(args))
+	var pipe redis.Pipeliner
+	for i, a := range args {
+		if a == nil {
+			continue
+		}
+		if pipe == nil {
+			pipe = g.Conn.Pipeline()
+		}
+		cmds[i] = pipe.Do(ctx, a...)
+	}
+	if pipe != nil {
+		_, _ = pipe.Exec(ctx)
+	}
+
+	failed := false
+	for i, cmd := range cmds {
+		if cmd == nil {
+			failed = failed || errs[i] != nil
+			continue
+		}
+		if err := cmd.Err(); err != nil {
+			errs[i] = err
+			failed = true
+			continue
+		}
+		results[i], errs[i] = QueryResultNew(g, cmd.Val())
+		failed = failed || errs[i] != nil
+	}
+	return failed
+}
@@ -0,0 +1,176 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/snowmerak/falkordb-go/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitPipeline(t *testing.T) {
+	g := NewGraphWithSchema(GraphSchemaWithData(nil, nil, nil))
+	args := [][]interface{}{
+		testArgs(t, g, CmdQuery, "RETURN 1", nil),
+		testArgs(t, g, CmdQuery, "RETURN 22", nil),
+		nil,
+		testArgs(t, g, CmdQuery, "RETURN 333", nil),
+		testArgs(t, g, CmdQuery, "RETURN 4444", nil),
+	}
+
+	assert.Equal(t, [][2]int{{0, 5}}, splitPipeline(args, 0, 0))
+	assert.Equal(t, [][2]int{{0, 2}, {2, 5}}, splitPipeline(args, 2, 0))
+	assert.Equal(t, [][2]int{{0, 1}, {1, 3}, {3, 4}, {4, 5}}, splitPipeline(args, 0, 10))
+	assert.Empty(t, splitPipeline(nil, 2, 0))
+}
+
+func TestPipelineWithOptionsReadOnly(t *testing.T) {
+	g := NewWithMode("g", nil, true)
+	reqs := []QueryRequest{{Query: "CREATE ()"}, {Command: CmdQuery, Query: "CREATE ()"}}
+
+	_, err := g.PipelineWithOptions(context.Background(), reqs, &PipelineOptions{ValidateReadOnly: true})
+	assert.ErrorIs(t, err, ErrReadOnly)
+
+	res, err := g.PipelineWithOptions(context.Background(), reqs, &PipelineOptions{BatchSize: 1})
+	assert.ErrorIs(t, err, ErrReadOnly)
+	assert.EqualError(t, err, "request 0: graph is read-only")
+	require.NotNil(t, res)
+	assert.Equal(t, []error{ErrReadOnly, ErrReadOnly}, res.Errors)
+	assert.Equal(t, []*QueryResult{nil, nil}, res.Results)
+}
+
+// replyHook answers the commands of a client without a server, so that
+// pipelines can be tested offline.
+type replyHook struct {
+	reply func(args []interface{}) (interface{}, error)
+}
+
+func (h replyHook) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (h replyHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		h.answer(cmd)
+		return cmd.Err()
+	}
+}
+
+func (h replyHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		var first error
+		for _, cmd := range cmds {
+			h.answer(cmd)
+			if first == nil {
+				first = cmd.Err()
+			}
+		}
+		return first
+	}
+}
+
+func (h replyHook) answer(cmd redis.Cmder) {
+	val, err := h.reply(cmd.Args())
+	if c, ok := cmd.(*redis.Cmd); ok {
+		c.SetVal(val)
+	}
+	cmd.SetErr(err)
+}
+
+func hookedGraph(t *testing.T, reply func(args []interface{}) (interface{}, error)) *Graph {
+	conn := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1"})
+	conn.AddHook(replyHook{reply: reply})
+	t.Cleanup(func() { conn.Close() })
+	return New("g", conn)
+}
+
+// testArgs returns the arguments of a query command without params.
+func testArgs(t *testing.T, g *Graph, command, query string, options *QueryOptions) []interface{} {
+	t.Helper()
+	args, err := g.commandArgs(command, query, nil, options)
+	require.NoError(t, err)
+	return args
+}
+
+func scalarReply(v int64) interface{} {
+	return []interface{}{
+		[]interface{}{[]interface{}{int64(COLUMN_SCALAR), "v"}},
+		[]interface{}{[]interface{}{[]interface{}{int64(VALUE_INTEGER), v}}},
+		[]interface{}{},
+	}
+}
+
+func TestPipelineWithOptionsStopOnError(t *testing.T) {
+	errFailed := errors.New("errMsg: failed")
+	g := hookedGraph(t, func(args []interface{}) (interface{}, error) {
+		if args[2] == "RETURN 1" {
+			return nil, errFailed
+		}
+		return scalarReply(0), nil
+	})
+	reqs := []QueryRequest{{Query: "RETURN 0"}, {Query: "RETURN 1"}, {Query: "RETURN 2"}, {Query: "RETURN 3"}}
+
+	res, err := g.PipelineWithOptions(context.Background(), reqs, &PipelineOptions{BatchSize: 1})
+	assert.ErrorIs(t, err, errFailed)
+	require.NotNil(t, res)
+	assert.Equal(t, []error{nil, errFailed, ErrNotSent, ErrNotSent}, res.Errors)
+	assert.NotNil(t, res.Results[0])
+	assert.Nil(t, res.Results[2])
+
+	// a request rejected before sending keeps its own error
+	g.defaults.ReadOnly = true
+	ro := []QueryRequest{{Command: CmdROQuery, Query: "RETURN 1"}, {Query: "CREATE ()"}, {Command: CmdROQuery, Query: "RETURN 2"}}
+	res, err = g.PipelineWithOptions(context.Background(), ro, &PipelineOptions{BatchSize: 1})
+	assert.ErrorIs(t, err, errFailed)
+	assert.Equal(t, []error{errFailed, ErrReadOnly, ErrNotSent}, res.Errors)
+	g.defaults.ReadOnly = false
+
+	res, err = g.PipelineWithOptions(context.Background(), reqs, &PipelineOptions{BatchSize: 1, ContinueOnError: true})
+	assert.ErrorIs(t, err, errFailed)
+	assert.Equal(t, []error{nil, errFailed, nil, nil}, res.Errors)
+	assert.NotNil(t, res.Results[3])
+}
+
+func TestPipelineWithOptionsConcurrentSchemaRefresh(t *testing.T) {
+	const n = 16
+	labels := make([]interface{}, n)
+	for i := range labels {
+		labels[i] = []interface{}{[]interface{}{int64(VALUE_STRING), fmt.Sprintf("L%d", i)}}
+	}
+	g := hookedGraph(t, func(args []interface{}) (interface{}, error) {
+		query := args[2].(string)
+		if query == "CALL db.labels()" {
+			return []interface{}{
+				[]interface{}{[]interface{}{int64(COLUMN_SCALAR), "label"}},
+				labels,
+				[]interface{}{},
+			}, nil
+		}
+		var id int64
+		if _, err := fmt.Sscanf(query, "MATCH (n:L%d) RETURN n", &id); err != nil {
+			return nil, err
+		}
+		node := []interface{}{id, []interface{}{id}, []interface{}{}}
+		return []interface{}{
+			[]interface{}{[]interface{}{int64(COLUMN_SCALAR), "n"}},
+			[]interface{}{[]interface{}{[]interface{}{int64(VALUE_NODE), node}}},
+			[]interface{}{},
+		}, nil
+	})
+
+	reqs := make([]QueryRequest, n)
+	for i := range reqs {
+		reqs[i] = QueryRequest{Command: CmdROQuery, Query: fmt.Sprintf("MATCH (n:L%d) RETURN n", i)}
+	}
+	// run with -race: every batch refreshes the labels while parsing
+	res, err := g.PipelineWithOptions(context.Background(), reqs, &PipelineOptions{BatchSize: 1, Concurrency: 8})
+	require.NoError(t, err)
+	for i, r := range res.Results {
+		require.True(t, r.Next())
+		node, ok := r.Record().GetByIndex(0).(*domain.Node)
+		require.True(t, ok)
+		assert.Equal(t, []string{fmt.Sprintf("L%d", i)}, node.Labels)
+	}
+}
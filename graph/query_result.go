@@ -6,22 +6,23 @@ import (
 	"os"
 	"strconv"
 	"strings"
-	"time"
 
-	"github.com/olekukonko/tablewriter"
-	"github.com/olekukonko/tablewriter/tw"
 	"github.com/snowmerak/falkordb-go/domain"
 )
 
 const (
 	LABELS_ADDED            string = "Labels added"
+	LABELS_REMOVED          string = "Labels removed"
 	NODES_CREATED           string = "Nodes created"
 	NODES_DELETED           string = "Nodes deleted"
 	RELATIONSHIPS_DELETED   string = "Relationships deleted"
 	PROPERTIES_SET          string = "Properties set"
+	PROPERTIES_REMOVED      string = "Properties removed"
 	RELATIONSHIPS_CREATED   string = "Relationships created"
 	INDICES_CREATED         string = "Indices created"
 	INDICES_DELETED         string = "Indices deleted"
+	CONSTRAINTS_CREATED     string = "Constraints created"
+	CONSTRAINTS_DELETED     string = "Constraints deleted"
 	INTERNAL_EXECUTION_TIME string = "Query internal execution time"
 	CACHED_EXECUTION        string = "Cached execution"
 )
@@ -68,21 +69,30 @@ type QueryResult struct {
 	header           QueryResultHeader
 	results          []*domain.Record
 	statistics       map[string]float64
+	stats            QueryStatistics
 	currentRecordIdx int
+	retries          int
 }
 
 // Graph returns the graph associated with this result set.
 func (qr *QueryResult) Graph() *Graph { return qr.graph }
 
+// Retries returns the number of times the query was retried before it
+// succeeded.
+func (qr *QueryResult) Retries() int { return qr.retries }
+
 // Header returns the parsed result header metadata.
 func (qr *QueryResult) Header() QueryResultHeader { return qr.header }
 
 // Results returns the raw records slice.
 func (qr *QueryResult) Results() []*domain.Record { return qr.results }
 
-// Statistics returns query execution statistics.
+// Statistics returns the numeric query execution statistics keyed by name.
 func (qr *QueryResult) Statistics() map[string]float64 { return qr.statistics }
 
+// Stats returns the typed query execution statistics.
+func (qr *QueryResult) Stats() QueryStatistics { return qr.stats }
+
 // CurrentRecordIndex returns the current cursor position, or -1 if iteration has not started.
 func (qr *QueryResult) CurrentRecordIndex() int { return qr.currentRecordIdx }
 
@@ -159,6 +169,7 @@ func (qr *QueryResult) parseStatistics(raw_statistics interface{}) error {
 		return fmt.Errorf("statistics payload is not array: %T", raw_statistics)
 	}
 	qr.statistics = make(map[string]float64)
+	qr.stats = QueryStatistics{}
 
 	for _, rs := range statistics {
 		rsStr, ok := rs.(string)
@@ -169,15 +180,13 @@ func (qr *QueryResult) parseStatistics(raw_statistics interface{}) error {
 		if len(parts) != 2 {
 			return fmt.Errorf("invalid statistic format: %s", rsStr)
 		}
-		numPart := strings.Split(parts[1], " ")
-		if len(numPart) == 0 || numPart[0] == "" {
-			return fmt.Errorf("missing statistic value in: %s", rsStr)
-		}
-		f, err := strconv.ParseFloat(numPart[0], 64)
+		f, numeric, err := qr.stats.set(parts[0], parts[1])
 		if err != nil {
-			return fmt.Errorf("invalid statistic value %q: %w", numPart[0], err)
+			return err
+		}
+		if numeric {
+			qr.statistics[parts[0]] = f
 		}
-		qr.statistics[parts[0]] = f
 	}
 
 	return nil
@@ -227,50 +236,59 @@ func (qr *QueryResult) parseRecords(raw_result_set []interface{}) error {
 	qr.results = make([]*domain.Record, len(records))
 
 	for i, r := range records {
-		cells, ok := r.([]interface{})
-		if !ok {
-			return fmt.Errorf("record %d is not array", i)
-		}
-		if len(cells) != len(qr.header.column_types) {
-			return fmt.Errorf("record %d column count mismatch: got %d want %d", i, len(cells), len(qr.header.column_types))
-		}
-
-		values := make([]interface{}, len(cells))
-
-		for idx, c := range cells {
-			t := qr.header.column_types[idx]
-			switch t {
-			case COLUMN_SCALAR:
-				cval, ok := c.([]interface{})
-				if !ok {
-					return fmt.Errorf("record %d column %d not scalar payload", i, idx)
-				}
-				s, err := qr.parseScalar(cval)
-				if err != nil {
-					return err
-				}
-				values[idx] = s
-			case COLUMN_NODE:
-				v, err := qr.parseNode(c)
-				if err != nil {
-					return err
-				}
-				values[idx] = v
-			case COLUMN_RELATION:
-				v, err := qr.parseEdge(c)
-				if err != nil {
-					return err
-				}
-				values[idx] = v
-			default:
-				return errors.New("unknown column type")
-			}
+		record, err := qr.parseRecord(i, r)
+		if err != nil {
+			return err
 		}
-		qr.results[i] = domain.NewRecord(values, qr.header.column_names)
+		qr.results[i] = record
 	}
 	return nil
 }
 
+// parseRecord decodes a single raw record according to the parsed header.
+func (qr *QueryResult) parseRecord(i int, r interface{}) (*domain.Record, error) {
+	cells, ok := r.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("record %d is not array", i)
+	}
+	if len(cells) != len(qr.header.column_types) {
+		return nil, fmt.Errorf("record %d column count mismatch: got %d want %d", i, len(cells), len(qr.header.column_types))
+	}
+
+	values := make([]interface{}, len(cells))
+
+	for idx, c := range cells {
+		t := qr.header.column_types[idx]
+		switch t {
+		case COLUMN_SCALAR:
+			cval, ok := c.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("record %d column %d not scalar payload", i, idx)
+			}
+			s, err := qr.parseScalar(cval)
+			if err != nil {
+				return nil, err
+			}
+			values[idx] = s
+		case COLUMN_NODE:
+			v, err := qr.parseNode(c)
+			if err != nil {
+				return nil, err
+			}
+			values[idx] = v
+		case COLUMN_RELATION:
+			v, err := qr.parseEdge(c)
+			if err != nil {
+				return nil, err
+			}
+			values[idx] = v
+		default:
+			return nil, errors.New("unknown column type")
+		}
+	}
+	return domain.NewRecord(values, qr.header.column_names), nil
+}
+
 func (qr *QueryResult) parseProperties(props []interface{}) (map[string]interface{}, error) {
 	// [[name, value type, value] X N]
 	properties := make(map[string]interface{})
@@ -283,7 +301,9 @@ func (qr *QueryResult) parseProperties(props []interface{}) (map[string]interfac
 		if !ok {
 			return nil, errors.New("property index not int64")
 		}
+		qr.graph.schemaMu.Lock()
 		prop_name, err := qr.graph.schema.getProperty(int(idx))
+		qr.graph.schemaMu.Unlock()
 		if err != nil {
 			return nil, err
 		}
@@ -320,7 +340,9 @@ func (qr *QueryResult) parseNode(cell interface{}) (*domain.Node, error) {
 		if !ok {
 			return nil, errors.New("label id not int64")
 		}
+		qr.graph.schemaMu.Lock()
 		label, err := qr.graph.schema.getLabel(int(lid))
+		qr.graph.schemaMu.Unlock()
 		if err != nil {
 			return nil, err
 		}
@@ -360,7 +382,9 @@ func (qr *QueryResult) parseEdge(cell interface{}) (*domain.Edge, error) {
 	if !ok {
 		return nil, errors.New("edge relation id not int64")
 	}
+	qr.graph.schemaMu.Lock()
 	relation, err := qr.graph.schema.getRelation(int(r))
+	qr.graph.schemaMu.Unlock()
 	if err != nil {
 		return nil, err
 	}
@@ -489,40 +513,37 @@ func (qr *QueryResult) parseMap(cell interface{}) (map[string]interface{}, error
 	return parsed_map, nil
 }
 
-func (qr *QueryResult) parsePoint(cell interface{}) (map[string]interface{}, error) {
-	parsed_point := make(map[string]interface{})
+func (qr *QueryResult) parsePoint(cell interface{}) (domain.Point, error) {
 	array, ok := cell.([]interface{})
 	if !ok || len(array) < 2 {
-		return nil, errors.New("point payload invalid")
+		return domain.Point{}, errors.New("point payload invalid")
 	}
 	latStr, ok := array[0].(string)
 	if !ok {
-		return nil, errors.New("point latitude not string")
+		return domain.Point{}, errors.New("point latitude not string")
 	}
 	lat, err := strconv.ParseFloat(latStr, 64)
 	if err != nil {
-		return nil, fmt.Errorf("invalid latitude: %w", err)
+		return domain.Point{}, fmt.Errorf("invalid latitude: %w", err)
 	}
-	parsed_point["latitude"] = lat
 	lonStr, ok := array[1].(string)
 	if !ok {
-		return nil, errors.New("point longitude not string")
+		return domain.Point{}, errors.New("point longitude not string")
 	}
 	lon, err := strconv.ParseFloat(lonStr, 64)
 	if err != nil {
-		return nil, fmt.Errorf("invalid longitude: %w", err)
+		return domain.Point{}, fmt.Errorf("invalid longitude: %w", err)
 	}
-	parsed_point["longitude"] = lon
-	return parsed_point, nil
+	return domain.NewPoint(lat, lon), nil
 }
 
-func (qr *QueryResult) parseVectorF32(cell interface{}) ([]float32, error) {
+func (qr *QueryResult) parseVectorF32(cell interface{}) (domain.Vector, error) {
 	array, ok := cell.([]interface{})
 	if !ok {
 		return nil, errors.New("vector payload not array")
 	}
 	var arrayLength = len(array)
-	var res = make([]float32, arrayLength)
+	var res = make(domain.Vector, arrayLength)
 	for i := 0; i < arrayLength; i++ {
 		f, ok := array[i].(float64)
 		if !ok {
@@ -600,34 +621,34 @@ func (qr *QueryResult) parseScalar(cell []interface{}) (interface{}, error) {
 		if !ok {
 			return nil, errors.New("localdatetime scalar not int64")
 		}
-		return time.Unix(i, 0), nil
+		return domain.LocalDateTimeFromUnix(i), nil
 
 	case VALUE_DATE:
 		i, ok := v.(int64)
 		if !ok {
 			return nil, errors.New("date scalar not int64")
 		}
-		return time.Unix(i, 0), nil
+		return domain.DateFromUnix(i), nil
 
 	case VALUE_LOCALTIME:
 		i, ok := v.(int64)
 		if !ok {
 			return nil, errors.New("localtime scalar not int64")
 		}
-		return time.Unix(i, 0), nil
+		return domain.LocalTimeFromUnix(i), nil
 
 	case VALUE_DURATION:
 		i, ok := v.(int64)
 		if !ok {
 			return nil, errors.New("duration scalar not int64")
 		}
-		return time.Duration(i) * time.Second, nil
+		return domain.Duration{Seconds: i}, nil
 
 	case VALUE_UNKNOWN:
 		return nil, errors.New("unknown scalar type")
 	}
 
-	return nil, errors.New("unknown scalar type")
+	return nil, fmt.Errorf("unknown scalar type %d, the server may be newer than this client", t)
 }
 
 func (qr *QueryResult) getStat(stat string) float64 {
@@ -651,6 +672,11 @@ func (qr *QueryResult) Next() bool {
 	}
 }
 
+// Reset rewinds the cursor so that Next starts again from the first record.
+func (qr *QueryResult) Reset() {
+	qr.currentRecordIdx = -1
+}
+
 // Record returns the current record.
 func (qr *QueryResult) Record() *domain.Record {
 	if qr.currentRecordIdx >= 0 && qr.currentRecordIdx < len(qr.results) {
@@ -666,36 +692,17 @@ func (qr *QueryResult) PrettyPrint() {
 		return
 	}
 
-	table := tablewriter.NewTable(os.Stdout, tablewriter.WithHeaderAutoFormat(tw.Off))
-	table.Header(qr.header.column_names)
-	row_count := len(qr.results)
-	col_count := len(qr.header.column_names)
-	if len(qr.results) > 0 {
-		// Convert to [][]string.
-		results := make([][]string, row_count)
-		for i, record := range qr.results {
-			results[i] = make([]string, col_count)
-			for j, elem := range record.Values() {
-				results[i][j] = fmt.Sprint(elem)
-			}
-		}
-		table.Bulk(results)
-	} else {
-		table.Append([]string{"No data returned."})
-	}
-	table.Render()
-
-	for k, v := range qr.statistics {
-		fmt.Fprintf(os.Stdout, "\n%s %f", k, v)
-	}
-
-	fmt.Fprintf(os.Stdout, "\n")
+	_ = qr.Render(os.Stdout, FormatTable)
 }
 
 func (qr *QueryResult) LabelsAdded() int {
 	return int(qr.getStat(LABELS_ADDED))
 }
 
+func (qr *QueryResult) LabelsRemoved() int {
+	return int(qr.getStat(LABELS_REMOVED))
+}
+
 func (qr *QueryResult) NodesCreated() int {
 	return int(qr.getStat(NODES_CREATED))
 }
@@ -708,6 +715,10 @@ func (qr *QueryResult) PropertiesSet() int {
 	return int(qr.getStat(PROPERTIES_SET))
 }
 
+func (qr *QueryResult) PropertiesRemoved() int {
+	return int(qr.getStat(PROPERTIES_REMOVED))
+}
+
 func (qr *QueryResult) RelationshipsCreated() int {
 	return int(qr.getStat(RELATIONSHIPS_CREATED))
 }
@@ -724,6 +735,14 @@ func (qr *QueryResult) IndicesDeleted() int {
 	return int(qr.getStat(INDICES_DELETED))
 }
 
+func (qr *QueryResult) ConstraintsCreated() int {
+	return int(qr.getStat(CONSTRAINTS_CREATED))
+}
+
+func (qr *QueryResult) ConstraintsDeleted() int {
+	return int(qr.getStat(CONSTRAINTS_DELETED))
+}
+
 // Returns the query internal execution time in milliseconds
 func (qr *QueryResult) InternalExecutionTime() float64 {
 	return qr.getStat(INTERNAL_EXECUTION_TIME)
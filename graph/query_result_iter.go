@@ -0,0 +1,44 @@
+//go:build go1.23
+
+package graph
+
+import (
+	"iter"
+
+	"github.com/snowmerak/falkordb-go/domain"
+)
+
+// All returns an iterator over the records and their indices. Unlike Next,
+// it does not touch the result cursor, so it can be used any number of times
+// and by several loops at once.
+func (qr *QueryResult) All() iter.Seq2[int, *domain.Record] {
+	return func(yield func(int, *domain.Record) bool) {
+		for i, r := range qr.results {
+			if !yield(i, r) {
+				return
+			}
+		}
+	}
+}
+
+// Column returns an iterator over the values of the named column.
+// It yields nothing if the result has no such column.
+func (qr *QueryResult) Column(name string) iter.Seq[any] {
+	return func(yield func(any) bool) {
+		idx := -1
+		for i, n := range qr.header.column_names {
+			if n == name {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return
+		}
+		for _, r := range qr.results {
+			if !yield(r.GetByIndex(idx)) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+//go:build go1.23
+
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func iterFixture(t *testing.T) *QueryResult {
+	t.Helper()
+	qr, err := QueryResultNew(&Graph{}, []interface{}{
+		[]interface{}{
+			[]interface{}{int64(COLUMN_SCALAR), "a"},
+			[]interface{}{int64(COLUMN_SCALAR), "b"},
+		},
+		[]interface{}{
+			[]interface{}{makeCell(VALUE_INTEGER, int64(1)), makeCell(VALUE_STRING, "x")},
+			[]interface{}{makeCell(VALUE_INTEGER, int64(2)), makeCell(VALUE_STRING, "y")},
+			[]interface{}{makeCell(VALUE_INTEGER, int64(3)), makeCell(VALUE_STRING, "z")},
+		},
+		[]interface{}{},
+	})
+	require.NoError(t, err)
+	return qr
+}
+
+func TestQueryResultAll(t *testing.T) {
+	qr := iterFixture(t)
+
+	var idx []int
+	for i, r := range qr.All() {
+		idx = append(idx, i)
+		// nested loops over the same result are independent
+		inner := 0
+		for range qr.All() {
+			inner++
+		}
+		assert.Equal(t, 3, inner)
+		assert.Equal(t, int64(i+1), r.GetByIndex(0))
+	}
+	assert.Equal(t, []int{0, 1, 2}, idx)
+	assert.Equal(t, -1, qr.CurrentRecordIndex(), "All must not move the cursor")
+
+	for i := range qr.All() {
+		if i == 1 {
+			break
+		}
+	}
+}
+
+func TestQueryResultColumn(t *testing.T) {
+	qr := iterFixture(t)
+
+	var got []any
+	for v := range qr.Column("b") {
+		got = append(got, v)
+	}
+	assert.Equal(t, []any{"x", "y", "z"}, got)
+
+	for range qr.Column("missing") {
+		t.Fatal("missing column should yield nothing")
+	}
+}
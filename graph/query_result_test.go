@@ -228,6 +228,12 @@ func TestParseScalar_EdgeCases(t *testing.T) {
 			wantErr:     true,
 			errContains: "unknown scalar type",
 		},
+		{
+			name:        "Scalar Type From A Newer Server",
+			cell:        []interface{}{int64(VALUE_DURATION + 1), "val"},
+			wantErr:     true,
+			errContains: "unknown scalar type 17, the server may be newer than this client",
+		},
 	}
 
 	for _, tt := range tests {
@@ -251,14 +257,14 @@ func TestParseDateTypes(t *testing.T) {
 	tests := []struct {
 		name        string
 		cell        []interface{}
-		wantType    string // Check specific types
+		want        interface{}
 		wantErr     bool
 		errContains string
 	}{
 		{
-			name:     "Date Success",
-			cell:     makeCell(VALUE_DATE, int64(1672531200)),
-			wantType: "time.Time",
+			name: "Date Success",
+			cell: makeCell(VALUE_DATE, int64(1672531200)),
+			want: domain.Date{Year: 2023, Month: time.January, Day: 1},
 		},
 		{
 			name:        "Date Invalid Type",
@@ -267,9 +273,12 @@ func TestParseDateTypes(t *testing.T) {
 			errContains: "date scalar not int64",
 		},
 		{
-			name:     "LocalDateTime Success",
-			cell:     makeCell(VALUE_LOCALDATETIME, int64(1672574400)),
-			wantType: "time.Time",
+			name: "LocalDateTime Success",
+			cell: makeCell(VALUE_LOCALDATETIME, int64(1672574400)),
+			want: domain.LocalDateTime{
+				Date: domain.Date{Year: 2023, Month: time.January, Day: 1},
+				Time: domain.LocalTime{Hour: 12},
+			},
 		},
 		{
 			name:        "LocalDateTime Invalid Type",
@@ -278,9 +287,9 @@ func TestParseDateTypes(t *testing.T) {
 			errContains: "localdatetime scalar not int64",
 		},
 		{
-			name:     "LocalTime Success",
-			cell:     makeCell(VALUE_LOCALTIME, int64(1000)),
-			wantType: "time.Time",
+			name: "LocalTime Success",
+			cell: makeCell(VALUE_LOCALTIME, int64(-2208945600)),
+			want: domain.LocalTime{Hour: 12},
 		},
 		{
 			name:        "LocalTime Invalid Type",
@@ -289,9 +298,9 @@ func TestParseDateTypes(t *testing.T) {
 			errContains: "localtime scalar not int64",
 		},
 		{
-			name:     "Duration Success",
-			cell:     makeCell(VALUE_DURATION, int64(3600)),
-			wantType: "time.Duration",
+			name: "Duration Success",
+			cell: makeCell(VALUE_DURATION, int64(3600)),
+			want: domain.Duration{Seconds: 3600},
 		},
 		{
 			name:        "Duration Invalid Type",
@@ -311,12 +320,48 @@ func TestParseDateTypes(t *testing.T) {
 				}
 			} else {
 				assert.NoError(t, err)
-				if tt.wantType == "time.Time" {
-					assert.IsType(t, time.Time{}, got)
-				} else if tt.wantType == "time.Duration" {
-					assert.IsType(t, time.Duration(0), got)
-				}
+				assert.Equal(t, tt.want, got)
 			}
 		})
 	}
 }
+
+func TestParseDateIgnoresLocalZone(t *testing.T) {
+	prev := time.Local
+	time.Local = time.FixedZone("UTC-10", -10*60*60)
+	defer func() { time.Local = prev }()
+
+	got, err := (&QueryResult{}).parseScalar(makeCell(VALUE_DATE, int64(1672531200)))
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Date{Year: 2023, Month: time.January, Day: 1}, got)
+}
+
+func TestParsePoint(t *testing.T) {
+	qr := &QueryResult{}
+
+	got, err := qr.parseScalar(makeCell(VALUE_POINT, []interface{}{"37.5", "-122.25"}))
+	assert.NoError(t, err)
+	assert.Equal(t, domain.NewPoint(37.5, -122.25), got)
+
+	_, err = qr.parseScalar(makeCell(VALUE_POINT, []interface{}{"north", "-122.25"}))
+	assert.ErrorContains(t, err, "invalid latitude")
+}
+
+func TestQueryResultReset(t *testing.T) {
+	qr, err := QueryResultNew(&Graph{}, []interface{}{
+		[]interface{}{[]interface{}{int64(COLUMN_SCALAR), "a"}},
+		[]interface{}{
+			[]interface{}{makeCell(VALUE_INTEGER, int64(1))},
+			[]interface{}{makeCell(VALUE_INTEGER, int64(2))},
+		},
+		[]interface{}{},
+	})
+	assert.NoError(t, err)
+	for qr.Next() {
+	}
+	assert.False(t, qr.Next())
+
+	qr.Reset()
+	assert.True(t, qr.Next())
+	assert.Equal(t, int64(1), qr.Record().GetByIndex(0))
+}
@@ -0,0 +1,472 @@
+package graph
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/tw"
+	"github.com/snowmerak/falkordb-go/domain"
+)
+
+// RenderFormat selects the output layout used by QueryResult.Render.
+type RenderFormat int
+
+const (
+	// FormatTable renders an ASCII table, the same layout PrettyPrint uses.
+	FormatTable RenderFormat = iota
+	// FormatCSV renders RFC 4180 comma separated values with a header row.
+	FormatCSV
+	// FormatTSV renders tab separated values with a header row.
+	FormatTSV
+	// FormatJSONLines renders one JSON object per record.
+	FormatJSONLines
+	// FormatMarkdown renders a GitHub flavoured markdown table.
+	FormatMarkdown
+	// FormatExpanded renders every record vertically, one column per line.
+	FormatExpanded
+)
+
+// String returns the name of the format.
+func (f RenderFormat) String() string {
+	switch f {
+	case FormatTable:
+		return "table"
+	case FormatCSV:
+		return "csv"
+	case FormatTSV:
+		return "tsv"
+	case FormatJSONLines:
+		return "jsonl"
+	case FormatMarkdown:
+		return "markdown"
+	case FormatExpanded:
+		return "expanded"
+	}
+	return fmt.Sprintf("RenderFormat(%d)", int(f))
+}
+
+// RenderOptions controls how a QueryResult is rendered.
+type RenderOptions struct {
+	// MaxValueWidth truncates rendered values longer than this many runes.
+	// Zero disables truncation. JSON Lines output is never truncated.
+	MaxValueWidth int
+	// OmitStatistics suppresses the statistics footer of the table,
+	// markdown and expanded formats. CSV, TSV and JSON Lines never
+	// include statistics.
+	OmitStatistics bool
+}
+
+type RenderOption func(*RenderOptions)
+
+// WithMaxValueWidth truncates rendered values to at most n runes.
+func WithMaxValueWidth(n int) RenderOption {
+	return func(o *RenderOptions) {
+		o.MaxValueWidth = n
+	}
+}
+
+// WithoutStatistics suppresses the statistics footer.
+func WithoutStatistics() RenderOption {
+	return func(o *RenderOptions) {
+		o.OmitStatistics = true
+	}
+}
+
+// statisticsOrder is the order in which known statistics are rendered;
+// statistics not listed here follow in alphabetical order.
+var statisticsOrder = []string{
+	LABELS_ADDED,
+	LABELS_REMOVED,
+	NODES_CREATED,
+	NODES_DELETED,
+	PROPERTIES_SET,
+	PROPERTIES_REMOVED,
+	RELATIONSHIPS_CREATED,
+	RELATIONSHIPS_DELETED,
+	INDICES_CREATED,
+	INDICES_DELETED,
+	CONSTRAINTS_CREATED,
+	CONSTRAINTS_DELETED,
+	CACHED_EXECUTION,
+	INTERNAL_EXECUTION_TIME,
+}
+
+// Render writes the QueryResult to w in the given format.
+func (qr *QueryResult) Render(w io.Writer, format RenderFormat, opts ...RenderOption) error {
+	options := &RenderOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	bw := bufio.NewWriter(w)
+	var err error
+	switch format {
+	case FormatTable:
+		err = qr.renderTable(bw, options)
+	case FormatCSV:
+		err = qr.renderDelimited(bw, ',', options)
+	case FormatTSV:
+		err = qr.renderTSV(bw, options)
+	case FormatJSONLines:
+		err = qr.renderJSONLines(bw)
+	case FormatMarkdown:
+		err = qr.renderMarkdown(bw, options)
+	case FormatExpanded:
+		err = qr.renderExpanded(bw, options)
+	default:
+		return fmt.Errorf("unknown render format %d", int(format))
+	}
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// rows converts every record into its rendered string cells.
+func (qr *QueryResult) rows(options *RenderOptions) [][]string {
+	rows := make([][]string, len(qr.results))
+	for i, record := range qr.results {
+		rows[i] = make([]string, len(qr.header.column_names))
+		for j, elem := range record.Values() {
+			if j < len(rows[i]) {
+				rows[i][j] = truncate(formatValue(elem, false), options.MaxValueWidth)
+			}
+		}
+	}
+	return rows
+}
+
+func (qr *QueryResult) renderTable(w io.Writer, options *RenderOptions) error {
+	if len(qr.header.column_names) > 0 {
+		table := tablewriter.NewTable(w, tablewriter.WithHeaderAutoFormat(tw.Off))
+		table.Header(qr.header.column_names)
+		if len(qr.results) > 0 {
+			if err := table.Bulk(qr.rows(options)); err != nil {
+				return err
+			}
+		} else if err := table.Append([]string{"No data returned."}); err != nil {
+			return err
+		}
+		if err := table.Render(); err != nil {
+			return err
+		}
+	}
+	return qr.renderStatistics(w, options, "")
+}
+
+func (qr *QueryResult) renderDelimited(w io.Writer, comma rune, options *RenderOptions) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+	if err := cw.Write(qr.header.column_names); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(qr.rows(options)); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+var tsvEscaper = strings.NewReplacer("\\", "\\\\", "\t", "\\t", "\n", "\\n", "\r", "\\r")
+
+func (qr *QueryResult) renderTSV(w io.Writer, options *RenderOptions) error {
+	writeLine := func(cells []string) error {
+		escaped := make([]string, len(cells))
+		for i, c := range cells {
+			escaped[i] = tsvEscaper.Replace(c)
+		}
+		_, err := io.WriteString(w, strings.Join(escaped, "\t")+"\n")
+		return err
+	}
+	if err := writeLine(qr.header.column_names); err != nil {
+		return err
+	}
+	for _, row := range qr.rows(options) {
+		if err := writeLine(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (qr *QueryResult) renderJSONLines(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, record := range qr.results {
+		obj := make(map[string]interface{}, len(qr.header.column_names))
+		for j, elem := range record.Values() {
+			if j < len(qr.header.column_names) {
+				obj[qr.header.column_names[j]] = jsonValue(elem)
+			}
+		}
+		if err := enc.Encode(obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var markdownEscaper = strings.NewReplacer("|", "\\|", "\r\n", "<br>", "\n", "<br>")
+
+func (qr *QueryResult) renderMarkdown(w io.Writer, options *RenderOptions) error {
+	if len(qr.header.column_names) > 0 {
+		writeLine := func(cells []string) error {
+			escaped := make([]string, len(cells))
+			for i, c := range cells {
+				escaped[i] = markdownEscaper.Replace(c)
+			}
+			_, err := io.WriteString(w, "| "+strings.Join(escaped, " | ")+" |\n")
+			return err
+		}
+		if err := writeLine(qr.header.column_names); err != nil {
+			return err
+		}
+		separator := make([]string, len(qr.header.column_names))
+		for i := range separator {
+			separator[i] = "---"
+		}
+		if err := writeLine(separator); err != nil {
+			return err
+		}
+		for _, row := range qr.rows(options) {
+			if err := writeLine(row); err != nil {
+				return err
+			}
+		}
+	}
+	return qr.renderStatistics(w, options, "\n")
+}
+
+func (qr *QueryResult) renderExpanded(w io.Writer, options *RenderOptions) error {
+	width := 0
+	for _, name := range qr.header.column_names {
+		if n := len([]rune(name)); n > width {
+			width = n
+		}
+	}
+	for i, row := range qr.rows(options) {
+		if _, err := fmt.Fprintf(w, "-[ RECORD %d ]%s\n", i+1, strings.Repeat("-", width+2)); err != nil {
+			return err
+		}
+		for j, cell := range row {
+			name := qr.header.column_names[j]
+			pad := strings.Repeat(" ", width-len([]rune(name)))
+			if _, err := fmt.Fprintf(w, "%s%s | %s\n", name, pad, cell); err != nil {
+				return err
+			}
+		}
+	}
+	if len(qr.results) == 0 && len(qr.header.column_names) > 0 {
+		if _, err := io.WriteString(w, "(no records)\n"); err != nil {
+			return err
+		}
+	}
+	return qr.renderStatistics(w, options, "\n")
+}
+
+// renderStatistics writes the statistics footer, one "name: value" line each.
+func (qr *QueryResult) renderStatistics(w io.Writer, options *RenderOptions, prefix string) error {
+	names := qr.statisticNames()
+	if options.OmitStatistics || len(names) == 0 {
+		return nil
+	}
+	if _, err := io.WriteString(w, prefix); err != nil {
+		return err
+	}
+	for _, name := range names {
+		value, ok := qr.stats.Unknown[name]
+		if !ok {
+			value = formatStatistic(name, qr.statistics[name])
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s\n", name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// statisticNames returns the reported statistic names in a stable rendering order.
+func (qr *QueryResult) statisticNames() []string {
+	names := make([]string, 0, len(qr.statistics)+len(qr.stats.Unknown))
+	known := make(map[string]bool, len(statisticsOrder))
+	for _, name := range statisticsOrder {
+		known[name] = true
+		if _, ok := qr.statistics[name]; ok {
+			names = append(names, name)
+		}
+	}
+	rest := make([]string, 0, len(qr.stats.Unknown))
+	for name := range qr.stats.Unknown {
+		rest = append(rest, name)
+	}
+	for name := range qr.statistics {
+		if _, ok := qr.stats.Unknown[name]; !ok && !known[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	return append(names, rest...)
+}
+
+// formatStatistic formats counters as integers and the execution time in milliseconds.
+func formatStatistic(name string, value float64) string {
+	if name == INTERNAL_EXECUTION_TIME {
+		return strconv.FormatFloat(value, 'f', -1, 64) + " milliseconds"
+	}
+	if value == float64(int64(value)) {
+		return strconv.FormatInt(int64(value), 10)
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// truncate shortens s to at most max runes, marking the cut with an ellipsis.
+func truncate(s string, max int) string {
+	if max <= 0 {
+		return s
+	}
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	if max == 1 {
+		return "…"
+	}
+	return string(r[:max-1]) + "…"
+}
+
+// formatValue renders a parsed value as text. Nested strings are quoted so
+// that containers stay unambiguous; map keys and properties are sorted.
+func formatValue(v interface{}, nested bool) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		if nested {
+			return strconv.Quote(val)
+		}
+		return val
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	case []interface{}:
+		parts := make([]string, len(val))
+		for i, e := range val {
+			parts[i] = formatValue(e, true)
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case domain.Vector:
+		return formatValue([]float32(val), nested)
+	case []float32:
+		parts := make([]string, len(val))
+		for i, e := range val {
+			parts[i] = strconv.FormatFloat(float64(e), 'g', -1, 32)
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case map[string]interface{}:
+		return formatProperties(val)
+	case *domain.Node:
+		var sb strings.Builder
+		sb.WriteString("(")
+		for _, label := range val.Labels {
+			sb.WriteString(":")
+			sb.WriteString(label)
+		}
+		if len(val.Properties) > 0 {
+			if len(val.Labels) > 0 {
+				sb.WriteString(" ")
+			}
+			sb.WriteString(formatProperties(val.Properties))
+		}
+		sb.WriteString(")")
+		return sb.String()
+	case *domain.Edge:
+		var sb strings.Builder
+		sb.WriteString("[:")
+		sb.WriteString(val.Relation)
+		if len(val.Properties) > 0 {
+			sb.WriteString(" ")
+			sb.WriteString(formatProperties(val.Properties))
+		}
+		sb.WriteString("]")
+		return sb.String()
+	case domain.Path:
+		return val.String()
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+func formatProperties(props map[string]interface{}) string {
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + ": " + formatValue(props[k], true)
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// jsonValue converts a parsed value into a structure encoding/json can marshal.
+func jsonValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, e := range val {
+			out[i] = jsonValue(e)
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, e := range val {
+			out[k] = jsonValue(e)
+		}
+		return out
+	case *domain.Node:
+		return map[string]interface{}{
+			"id":         val.ID,
+			"labels":     val.Labels,
+			"properties": jsonValue(val.Properties),
+		}
+	case *domain.Edge:
+		return map[string]interface{}{
+			"id":         val.ID,
+			"type":       val.Relation,
+			"src":        val.GetSourceNodeID(),
+			"dst":        val.GetDestNodeID(),
+			"properties": jsonValue(val.Properties),
+		}
+	case domain.Path:
+		nodes := make([]interface{}, len(val.Nodes))
+		for i, n := range val.Nodes {
+			nodes[i] = jsonValue(n)
+		}
+		edges := make([]interface{}, len(val.Edges))
+		for i, e := range val.Edges {
+			edges[i] = jsonValue(e)
+		}
+		return map[string]interface{}{"nodes": nodes, "edges": edges}
+	case time.Duration:
+		return val.String()
+	case domain.Point:
+		return map[string]interface{}{"latitude": val.Latitude, "longitude": val.Longitude}
+	case domain.Date, domain.LocalTime, domain.LocalDateTime, domain.Duration:
+		return fmt.Sprint(val)
+	default:
+		return val
+	}
+}
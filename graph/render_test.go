@@ -0,0 +1,117 @@
+package graph
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func renderFixture(t *testing.T) *QueryResult {
+	t.Helper()
+	g := NewGraphWithSchema(GraphSchemaWithData(
+		[]string{"Person"},
+		[]string{"KNOWS"},
+		[]string{"name", "age"},
+	))
+
+	node := []interface{}{
+		int64(7),
+		[]interface{}{int64(0)},
+		[]interface{}{
+			[]interface{}{int64(1), int64(VALUE_INTEGER), int64(33)},
+			[]interface{}{int64(0), int64(VALUE_STRING), "John"},
+		},
+	}
+	response := []interface{}{
+		[]interface{}{
+			[]interface{}{int64(COLUMN_SCALAR), "name"},
+			[]interface{}{int64(COLUMN_SCALAR), "n"},
+		},
+		[]interface{}{
+			[]interface{}{makeCell(VALUE_STRING, "John, \"JD\" Doe"), makeCell(VALUE_NODE, node)},
+			[]interface{}{makeCell(VALUE_NULL, nil), makeCell(VALUE_NULL, nil)},
+		},
+		[]interface{}{
+			"Query internal execution time: 0.25 milliseconds",
+			"Nodes created: 2",
+			"Labels added: 1",
+			"Cached execution: 0",
+		},
+	}
+
+	qr, err := QueryResultNew(g, response)
+	require.NoError(t, err)
+	return qr
+}
+
+func render(t *testing.T, qr *QueryResult, format RenderFormat, opts ...RenderOption) string {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, qr.Render(&buf, format, opts...))
+	return buf.String()
+}
+
+func TestRenderCSV(t *testing.T) {
+	qr := renderFixture(t)
+	want := "name,n\n" +
+		"\"John, \"\"JD\"\" Doe\",\"(:Person {age: 33, name: \"\"John\"\"})\"\n" +
+		"null,null\n"
+	assert.Equal(t, want, render(t, qr, FormatCSV))
+}
+
+func TestRenderTSV(t *testing.T) {
+	qr := renderFixture(t)
+	want := "name\tn\n" +
+		"John, \"JD\" Doe\t(:Person {age: 33, name: \"John\"})\n" +
+		"null\tnull\n"
+	assert.Equal(t, want, render(t, qr, FormatTSV))
+}
+
+func TestRenderJSONLines(t *testing.T) {
+	qr := renderFixture(t)
+	want := `{"n":{"id":7,"labels":["Person"],"properties":{"age":33,"name":"John"}},"name":"John, \"JD\" Doe"}` + "\n" +
+		`{"n":null,"name":null}` + "\n"
+	assert.Equal(t, want, render(t, qr, FormatJSONLines))
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	qr := renderFixture(t)
+	want := "| name | n |\n" +
+		"| --- | --- |\n" +
+		"| John, \"JD\" Doe | (:Person {age: 33, name: \"John\"}) |\n" +
+		"| null | null |\n" +
+		"\n" +
+		"Labels added: 1\n" +
+		"Nodes created: 2\n" +
+		"Cached execution: 0\n" +
+		"Query internal execution time: 0.25 milliseconds\n"
+	assert.Equal(t, want, render(t, qr, FormatMarkdown))
+}
+
+func TestRenderExpandedTruncated(t *testing.T) {
+	qr := renderFixture(t)
+	want := "-[ RECORD 1 ]------\n" +
+		"name | John, \"JD…\n" +
+		"n    | (:Person …\n" +
+		"-[ RECORD 2 ]------\n" +
+		"name | null\n" +
+		"n    | null\n"
+	assert.Equal(t, want, render(t, qr, FormatExpanded, WithMaxValueWidth(10), WithoutStatistics()))
+}
+
+func TestRenderTableIsStable(t *testing.T) {
+	qr := renderFixture(t)
+	first := render(t, qr, FormatTable)
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, first, render(t, qr, FormatTable))
+	}
+	assert.Contains(t, first, "Nodes created: 2\n")
+}
+
+func TestRenderUnknownFormat(t *testing.T) {
+	qr := renderFixture(t)
+	var buf bytes.Buffer
+	assert.Error(t, qr.Render(&buf, RenderFormat(99)))
+}
@@ -0,0 +1,140 @@
+package graph
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// respError is an error reply sent by the server. It satisfies redis.Error.
+type respError string
+
+func (e respError) Error() string { return string(e) }
+
+// RedisError marks respError as a server reply error.
+func (e respError) RedisError() {}
+
+// respReader decodes RESP2 replies one value at a time, so that large
+// replies can be consumed without materialising them in memory.
+type respReader struct {
+	rd *bufio.Reader
+}
+
+func newRESPReader(r io.Reader) *respReader {
+	return &respReader{rd: bufio.NewReaderSize(r, 32*1024)}
+}
+
+func (r *respReader) readLine() ([]byte, error) {
+	line, err := r.rd.ReadSlice('\n')
+	if err != nil {
+		if errors.Is(err, bufio.ErrBufferFull) {
+			return nil, errors.New("resp: line too long")
+		}
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("resp: invalid line %q", line)
+	}
+	return line[:len(line)-2], nil
+}
+
+// readArrayLen reads an array header and returns its length. An error reply
+// is returned as a respError.
+func (r *respReader) readArrayLen() (int, error) {
+	line, err := r.readLine()
+	if err != nil {
+		return 0, err
+	}
+	switch line[0] {
+	case '*':
+		n, err := strconv.Atoi(string(line[1:]))
+		if err != nil {
+			return 0, fmt.Errorf("resp: invalid array length %q", line[1:])
+		}
+		return n, nil
+	case '-':
+		return 0, respError(line[1:])
+	default:
+		return 0, fmt.Errorf("resp: expected array, got %q", line)
+	}
+}
+
+// readValue reads a complete value using the same Go types go-redis produces:
+// string, int64, []interface{} and nil. Error replies are returned as a
+// respError value rather than as the error result, since they may be nested.
+func (r *respReader) readValue() (interface{}, error) {
+	line, err := r.readLine()
+	if err != nil {
+		return nil, err
+	}
+	switch line[0] {
+	case '+':
+		return string(line[1:]), nil
+	case '-':
+		return respError(line[1:]), nil
+	case ':':
+		n, err := strconv.ParseInt(string(line[1:]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("resp: invalid integer %q", line[1:])
+		}
+		return n, nil
+	case '$':
+		n, err := strconv.Atoi(string(line[1:]))
+		if err != nil {
+			return nil, fmt.Errorf("resp: invalid bulk length %q", line[1:])
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r.rd, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(string(line[1:]))
+		if err != nil {
+			return nil, fmt.Errorf("resp: invalid array length %q", line[1:])
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		arr := make([]interface{}, n)
+		for i := range arr {
+			if arr[i], err = r.readValue(); err != nil {
+				return nil, err
+			}
+		}
+		return arr, nil
+	default:
+		return nil, fmt.Errorf("resp: unexpected reply type %q", line[0])
+	}
+}
+
+// appendCommand encodes a command as a RESP array of bulk strings.
+func appendCommand(dst []byte, args ...interface{}) []byte {
+	dst = append(dst, '*')
+	dst = strconv.AppendInt(dst, int64(len(args)), 10)
+	dst = append(dst, '\r', '\n')
+	for _, arg := range args {
+		var s string
+		switch v := arg.(type) {
+		case string:
+			s = v
+		case int:
+			s = strconv.Itoa(v)
+		case int64:
+			s = strconv.FormatInt(v, 10)
+		default:
+			s = fmt.Sprint(v)
+		}
+		dst = append(dst, '$')
+		dst = strconv.AppendInt(dst, int64(len(s)), 10)
+		dst = append(dst, '\r', '\n')
+		dst = append(dst, s...)
+		dst = append(dst, '\r', '\n')
+	}
+	return dst
+}
@@ -0,0 +1,183 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RetryPolicy retries queries that failed with a transient error. Only read
+// only queries and queries marked idempotent with QueryOptions.SetIdempotent
+// are retried.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt.
+	MaxRetries int
+	// Backoff is the delay before the first retry, doubled for every
+	// following retry.
+	Backoff time.Duration
+	// MaxBackoff caps the delay between retries. Zero means no cap.
+	MaxBackoff time.Duration
+	// Jitter randomly shortens every delay by up to this fraction of it,
+	// between 0 and 1.
+	Jitter float64
+	// Budget limits retries across all queries sharing it; nil means no limit.
+	Budget *RetryBudget
+	// Classify reports whether an error is transient. Nil uses IsTransient.
+	Classify func(err error) bool
+}
+
+// delay returns the delay before retry n, counted from zero.
+func (p *RetryPolicy) delay(n int) time.Duration {
+	d := p.Backoff
+	for i := 0; i < n && (p.MaxBackoff <= 0 || d < p.MaxBackoff); i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if p.Jitter > 0 {
+		d -= time.Duration(rand.Float64() * p.Jitter * float64(d))
+	}
+	return d
+}
+
+func (p *RetryPolicy) transient(err error) bool {
+	if p.Classify != nil {
+		return p.Classify(err)
+	}
+	return IsTransient(err)
+}
+
+// RetryBudget limits retries to a fraction of the queries, so that retries do
+// not multiply the load on a struggling server. Every query earns Ratio
+// tokens and every retry spends one; at most Burst tokens are kept.
+type RetryBudget struct {
+	mu     sync.Mutex
+	ratio  float64
+	burst  float64
+	tokens float64
+}
+
+// NewRetryBudget creates a budget allowing retries for ratio of the queries,
+// on top of an initial burst of retries.
+func NewRetryBudget(ratio float64, burst int) *RetryBudget {
+	return &RetryBudget{ratio: ratio, burst: float64(burst), tokens: float64(burst)}
+}
+
+func (b *RetryBudget) deposit() {
+	b.mu.Lock()
+	b.tokens += b.ratio
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.mu.Unlock()
+}
+
+func (b *RetryBudget) withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// RetryError is returned when a query still failed after being retried.
+type RetryError struct {
+	// Retries is the number of retries made.
+	Retries int
+	Err     error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("after %d retries: %v", e.Retries, e.Err)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// IsTransient reports whether err is a failure that a later attempt may not
+// hit: a network error, a connection closed by the server, a server that is
+// loading or failing over, a cluster that is down or resharding, or a server
+// rejecting the query because too many queries are pending. Context errors,
+// query errors and errors parsing a reply are not transient.
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	// Dial timeouts match context.DeadlineExceeded too, so failed network
+	// operations are checked first.
+	var operr *net.OpError
+	if errors.As(err, &operr) {
+		return true
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var nerr net.Error
+	if errors.As(err, &nerr) {
+		return true
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var rerr redis.Error
+	if !errors.As(err, &rerr) {
+		return false
+	}
+	msg := err.Error()
+	for _, prefix := range []string{"LOADING", "TRYAGAIN", "CLUSTERDOWN", "MASTERDOWN"} {
+		if strings.HasPrefix(msg, prefix) {
+			return true
+		}
+	}
+	return strings.Contains(strings.ToLower(msg), "max pending queries exceeded")
+}
+
+// retry executes req, retrying transient failures as the graph's retry
+// policy allows.
+func (g *Graph) retry(ctx context.Context, req QueryRequest) (*QueryResult, error) {
+	policy := g.defaults.Retry
+	res, err := g.exec(ctx, req)
+	if policy == nil || !(req.Command == CmdROQuery || g.defaults.Options.Merge(req.Options).Idempotent()) {
+		return res, err
+	}
+	if policy.Budget != nil {
+		policy.Budget.deposit()
+	}
+
+	retries := 0
+	for err != nil && retries < policy.MaxRetries && ctx.Err() == nil && policy.transient(err) {
+		if policy.Budget != nil && !policy.Budget.withdraw() {
+			break
+		}
+		t := time.NewTimer(policy.delay(retries))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, &RetryError{Retries: retries, Err: ctx.Err()}
+		case <-t.C:
+		}
+		retries++
+		res, err = g.exec(ctx, req)
+	}
+
+	if err != nil {
+		if retries > 0 {
+			err = &RetryError{Retries: retries, Err: err}
+		}
+		return nil, err
+	}
+	res.retries = retries
+	return res, nil
+}
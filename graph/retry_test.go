@@ -0,0 +1,110 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsTransient(t *testing.T) {
+	assert.True(t, IsTransient(&net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}))
+	assert.True(t, IsTransient(fmt.Errorf("read reply: %w", io.ErrUnexpectedEOF)))
+	assert.True(t, IsTransient(testRedisError("LOADING Redis is loading the dataset in memory")))
+	assert.True(t, IsTransient(testRedisError("TRYAGAIN Multiple keys request during rehashing of slot")))
+	assert.True(t, IsTransient(testRedisError("CLUSTERDOWN The cluster is down")))
+	assert.True(t, IsTransient(testRedisError("Max pending queries exceeded")))
+	assert.False(t, IsTransient(testRedisError("Query timed out")))
+	assert.False(t, IsTransient(testRedisError("errMsg: Invalid input")))
+	assert.False(t, IsTransient(context.DeadlineExceeded))
+	assert.False(t, IsTransient(fmt.Errorf("query: %w", context.DeadlineExceeded)))
+	assert.False(t, IsTransient(errors.New("unknown execution time unit")))
+	assert.False(t, IsTransient(redis.ErrClosed))
+	assert.False(t, IsTransient(ErrReadOnly))
+	assert.False(t, IsTransient(nil))
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	p := &RetryPolicy{Backoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond}
+	assert.Equal(t, 10*time.Millisecond, p.delay(0))
+	assert.Equal(t, 20*time.Millisecond, p.delay(1))
+	assert.Equal(t, 40*time.Millisecond, p.delay(2))
+	assert.Equal(t, 50*time.Millisecond, p.delay(3))
+	assert.Equal(t, 50*time.Millisecond, p.delay(40))
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		d := p.delay(0)
+		assert.True(t, d > 5*time.Millisecond && d <= 10*time.Millisecond, d)
+	}
+}
+
+func TestRetryBudget(t *testing.T) {
+	b := NewRetryBudget(0.5, 1)
+	assert.True(t, b.withdraw())
+	assert.False(t, b.withdraw())
+	b.deposit()
+	assert.False(t, b.withdraw())
+	b.deposit()
+	assert.True(t, b.withdraw())
+	for i := 0; i < 10; i++ {
+		b.deposit()
+	}
+	assert.True(t, b.withdraw())
+	assert.False(t, b.withdraw())
+}
+
+func unreachableGraph(t *testing.T) *Graph {
+	conn := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1, DialTimeout: 20 * time.Millisecond})
+	t.Cleanup(func() { conn.Close() })
+	return NewWithMode("g", conn, false)
+}
+
+func TestRetryTransientErrors(t *testing.T) {
+	g := unreachableGraph(t)
+	g.SetDefaults(Defaults{Retry: &RetryPolicy{MaxRetries: 2, Backoff: time.Millisecond}})
+
+	_, err := g.ROQuery("RETURN 1", nil, nil)
+	var rerr *RetryError
+	require.ErrorAs(t, err, &rerr)
+	assert.Equal(t, 2, rerr.Retries)
+
+	_, err = g.Query("CREATE ()", nil, nil)
+	assert.Error(t, err)
+	assert.False(t, errors.As(err, &rerr))
+
+	_, err = g.Query("MERGE (:N {id: 1})", nil, NewQueryOptions().SetIdempotent(true))
+	require.ErrorAs(t, err, &rerr)
+	assert.Equal(t, 2, rerr.Retries)
+}
+
+func TestRetryClassifierAndBudget(t *testing.T) {
+	g := unreachableGraph(t)
+	g.SetDefaults(Defaults{Retry: &RetryPolicy{
+		MaxRetries: 5,
+		Backoff:    time.Millisecond,
+		Classify:   func(error) bool { return false },
+	}})
+	_, err := g.ROQuery("RETURN 1", nil, nil)
+	var rerr *RetryError
+	assert.False(t, errors.As(err, &rerr))
+
+	g.SetDefaults(Defaults{Retry: &RetryPolicy{
+		MaxRetries: 5,
+		Backoff:    time.Millisecond,
+		Budget:     NewRetryBudget(0, 3),
+	}})
+	_, err = g.ROQuery("RETURN 1", nil, nil)
+	require.ErrorAs(t, err, &rerr)
+	assert.Equal(t, 3, rerr.Retries)
+	_, err = g.ROQuery("RETURN 1", nil, nil)
+	assert.False(t, errors.As(err, &rerr))
+}
@@ -0,0 +1,149 @@
+package graph
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// snapshotMagic starts every snapshot envelope.
+const snapshotMagic = "FDBS"
+
+// SnapshotVersion is the envelope format version written by Snapshot.
+const SnapshotVersion = 1
+
+var snapshotTable = crc32.MakeTable(crc32.Castagnoli)
+
+// SnapshotInfo describes a snapshot envelope.
+type SnapshotInfo struct {
+	// Version is the envelope format version.
+	Version int
+	// ModuleVersion is the FalkorDB module version the graph was dumped from.
+	ModuleVersion int
+	// Graph is the name of the dumped graph.
+	Graph string
+	// Size is the length of the DUMP payload.
+	Size int
+	// Checksum is the CRC-32C of the DUMP payload.
+	Checksum uint32
+}
+
+// Snapshot returns an exact binary copy of the graph, taken with DUMP on the
+// graph key and wrapped in an envelope carrying a checksum and the module
+// version. Restore it with FalkorDB.RestoreGraph.
+func (g *Graph) Snapshot(ctx context.Context) ([]byte, error) {
+	info, payload, err := g.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	buf := appendSnapshotHeader(make([]byte, 0, 32+len(info.Graph)+len(payload)), info)
+	return append(buf, payload...), nil
+}
+
+// SnapshotTo writes a snapshot of the graph to w.
+func (g *Graph) SnapshotTo(ctx context.Context, w io.Writer) error {
+	info, payload, err := g.snapshot(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(appendSnapshotHeader(nil, info)); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, payload)
+	return err
+}
+
+func (g *Graph) snapshot(ctx context.Context) (SnapshotInfo, string, error) {
+	if _, err := g.enter(); err != nil {
+		return SnapshotInfo{}, "", err
+	}
+	defer g.leave()
+
+	version, err := g.ModuleVersion(ctx)
+	if err != nil {
+		return SnapshotInfo{}, "", err
+	}
+	payload, err := g.Conn.Dump(ctx, g.Id).Result()
+	if err != nil {
+		return SnapshotInfo{}, "", err
+	}
+	info := SnapshotInfo{
+		Version:       SnapshotVersion,
+		ModuleVersion: version,
+		Graph:         g.Id,
+		Size:          len(payload),
+		Checksum:      crc32.Checksum([]byte(payload), snapshotTable),
+	}
+	return info, payload, nil
+}
+
+// appendSnapshotHeader encodes the envelope header:
+// magic, version (uint16), module version (uint32), graph name length
+// (uint16) and name, payload length (uint64) and checksum (uint32).
+func appendSnapshotHeader(dst []byte, info SnapshotInfo) []byte {
+	dst = append(dst, snapshotMagic...)
+	dst = binary.BigEndian.AppendUint16(dst, uint16(info.Version))
+	dst = binary.BigEndian.AppendUint32(dst, uint32(info.ModuleVersion))
+	dst = binary.BigEndian.AppendUint16(dst, uint16(len(info.Graph)))
+	dst = append(dst, info.Graph...)
+	dst = binary.BigEndian.AppendUint64(dst, uint64(info.Size))
+	return binary.BigEndian.AppendUint32(dst, info.Checksum)
+}
+
+// ReadSnapshot reads a snapshot envelope from r and returns its metadata
+// and the verified DUMP payload.
+func ReadSnapshot(r io.Reader) (SnapshotInfo, []byte, error) {
+	rd := bufio.NewReader(r)
+	var info SnapshotInfo
+
+	var fixed [12]byte
+	if _, err := io.ReadFull(rd, fixed[:]); err != nil {
+		return info, nil, fmt.Errorf("reading snapshot header: %w", err)
+	}
+	if string(fixed[:4]) != snapshotMagic {
+		return info, nil, errors.New("not a graph snapshot")
+	}
+	info.Version = int(binary.BigEndian.Uint16(fixed[4:6]))
+	if info.Version != SnapshotVersion {
+		return info, nil, fmt.Errorf("unsupported snapshot version %d", info.Version)
+	}
+	info.ModuleVersion = int(binary.BigEndian.Uint32(fixed[6:10]))
+
+	name := make([]byte, binary.BigEndian.Uint16(fixed[10:12]))
+	if _, err := io.ReadFull(rd, name); err != nil {
+		return info, nil, fmt.Errorf("reading snapshot header: %w", err)
+	}
+	info.Graph = string(name)
+
+	var tail [12]byte
+	if _, err := io.ReadFull(rd, tail[:]); err != nil {
+		return info, nil, fmt.Errorf("reading snapshot header: %w", err)
+	}
+	size := binary.BigEndian.Uint64(tail[:8])
+	info.Checksum = binary.BigEndian.Uint32(tail[8:])
+	if size > 1<<40 {
+		return info, nil, fmt.Errorf("invalid snapshot size %d", size)
+	}
+	info.Size = int(size)
+
+	// The buffer grows with the data actually read, so a corrupt size
+	// cannot allocate more memory than the input holds.
+	var payload bytes.Buffer
+	h := crc32.New(snapshotTable)
+	n, err := io.Copy(io.MultiWriter(&payload, h), io.LimitReader(rd, int64(size)))
+	if err != nil {
+		return info, nil, fmt.Errorf("reading snapshot payload: %w", err)
+	}
+	if n != int64(size) {
+		return info, nil, fmt.Errorf("reading snapshot payload: %w", io.ErrUnexpectedEOF)
+	}
+	if h.Sum32() != info.Checksum {
+		return info, nil, errors.New("snapshot checksum mismatch")
+	}
+	return info, payload.Bytes(), nil
+}
@@ -0,0 +1,80 @@
+package graph
+
+import (
+	"bytes"
+	"hash/crc32"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func snapshotBlob(payload string) []byte {
+	info := SnapshotInfo{
+		Version:       SnapshotVersion,
+		ModuleVersion: 41000,
+		Graph:         "social",
+		Size:          len(payload),
+		Checksum:      crc32.Checksum([]byte(payload), snapshotTable),
+	}
+	return append(appendSnapshotHeader(nil, info), payload...)
+}
+
+func TestReadSnapshot(t *testing.T) {
+	info, payload, err := ReadSnapshot(bytes.NewReader(snapshotBlob("\x00dump-payload")))
+	require.NoError(t, err)
+	assert.Equal(t, "\x00dump-payload", string(payload))
+	assert.Equal(t, SnapshotInfo{
+		Version:       SnapshotVersion,
+		ModuleVersion: 41000,
+		Graph:         "social",
+		Size:          13,
+		Checksum:      crc32.Checksum(payload, snapshotTable),
+	}, info)
+}
+
+func TestReadSnapshotInvalid(t *testing.T) {
+	blob := snapshotBlob("dump-payload")
+
+	corrupt := append([]byte(nil), blob...)
+	corrupt[len(corrupt)-1] ^= 0xff
+	_, _, err := ReadSnapshot(bytes.NewReader(corrupt))
+	assert.EqualError(t, err, "snapshot checksum mismatch")
+
+	_, _, err = ReadSnapshot(bytes.NewReader(blob[:len(blob)-3]))
+	assert.ErrorContains(t, err, "reading snapshot payload")
+
+	_, _, err = ReadSnapshot(bytes.NewReader([]byte("REDIS0011")))
+	assert.Error(t, err)
+
+	// a header claiming a huge payload fails on the short input instead of
+	// allocating the claimed size up front
+	info := SnapshotInfo{Version: SnapshotVersion, Graph: "social", Size: 1 << 40}
+	huge := append(appendSnapshotHeader(nil, info), "dump-payload"...)
+	_, _, err = ReadSnapshot(bytes.NewReader(huge))
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	future := append([]byte(nil), blob...)
+	future[5] = 9
+	_, _, err = ReadSnapshot(bytes.NewReader(future))
+	assert.EqualError(t, err, "unsupported snapshot version 9")
+}
+
+func TestParseModuleVersion(t *testing.T) {
+	resp3 := []interface{}{
+		map[interface{}]interface{}{"name": "search", "ver": int64(20000)},
+		map[interface{}]interface{}{"name": "graph", "ver": int64(41000)},
+	}
+	v, err := parseModuleVersion(resp3)
+	require.NoError(t, err)
+	assert.Equal(t, 41000, v)
+
+	resp2 := []interface{}{[]interface{}{"name", "graph", "ver", int64(40200), "path", "/falkordb.so"}}
+	v, err = parseModuleVersion(resp2)
+	require.NoError(t, err)
+	assert.Equal(t, 40200, v)
+
+	_, err = parseModuleVersion([]interface{}{})
+	assert.EqualError(t, err, "graph module not loaded")
+}
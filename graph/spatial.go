@@ -0,0 +1,36 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/snowmerak/falkordb-go/domain"
+)
+
+// NodesWithinDistance returns the nodes with the given label whose point
+// property prop lies within meters of center, nearest first.
+func (g *Graph) NodesWithinDistance(ctx context.Context, label, prop string, center domain.Point, meters float64) ([]*domain.Node, error) {
+	query := fmt.Sprintf(
+		"MATCH (n:%[1]s) WHERE distance(n.%[2]s, $center) <= $meters RETURN n ORDER BY distance(n.%[2]s, $center)",
+		QuoteIdentifier(label), QuoteIdentifier(prop),
+	)
+	params := map[string]interface{}{
+		"center": center,
+		"meters": meters,
+	}
+
+	qr, err := g.query(ctx, CmdROQuery, query, params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]*domain.Node, 0, len(qr.results))
+	for _, r := range qr.results {
+		n, ok := r.GetByIndex(0).(*domain.Node)
+		if !ok {
+			return nil, fmt.Errorf("unexpected result type %T", r.GetByIndex(0))
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes, nil
+}
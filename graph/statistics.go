@@ -0,0 +1,193 @@
+package graph
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QueryStatistics holds the typed execution statistics reported by the server.
+type QueryStatistics struct {
+	LabelsAdded          int
+	LabelsRemoved        int
+	NodesCreated         int
+	NodesDeleted         int
+	PropertiesSet        int
+	PropertiesRemoved    int
+	RelationshipsCreated int
+	RelationshipsDeleted int
+	IndicesCreated       int
+	IndicesDeleted       int
+	ConstraintsCreated   int
+	ConstraintsDeleted   int
+	// CachedExecution reports whether the query plan was served from the cache.
+	CachedExecution bool
+	// InternalExecutionTime is the server side execution time of the query.
+	InternalExecutionTime time.Duration
+	// Unknown keeps statistics this client version does not recognise,
+	// with their raw textual value, including an execution time in an
+	// unknown unit.
+	Unknown map[string]string
+}
+
+// counter returns the field backing a known counter statistic, or nil.
+func (s *QueryStatistics) counter(name string) *int {
+	switch name {
+	case LABELS_ADDED:
+		return &s.LabelsAdded
+	case LABELS_REMOVED:
+		return &s.LabelsRemoved
+	case NODES_CREATED:
+		return &s.NodesCreated
+	case NODES_DELETED:
+		return &s.NodesDeleted
+	case PROPERTIES_SET:
+		return &s.PropertiesSet
+	case PROPERTIES_REMOVED:
+		return &s.PropertiesRemoved
+	case RELATIONSHIPS_CREATED:
+		return &s.RelationshipsCreated
+	case RELATIONSHIPS_DELETED:
+		return &s.RelationshipsDeleted
+	case INDICES_CREATED:
+		return &s.IndicesCreated
+	case INDICES_DELETED:
+		return &s.IndicesDeleted
+	case CONSTRAINTS_CREATED:
+		return &s.ConstraintsCreated
+	case CONSTRAINTS_DELETED:
+		return &s.ConstraintsDeleted
+	}
+	return nil
+}
+
+// set records a single "name: value" statistic. It returns the numeric value
+// of the statistic and whether the value was numeric.
+func (s *QueryStatistics) set(name, value string) (float64, bool, error) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return 0, false, fmt.Errorf("missing statistic value in: %s: %s", name, value)
+	}
+
+	if c := s.counter(name); c != nil {
+		f, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("invalid statistic value %q: %w", fields[0], err)
+		}
+		*c = int(f)
+		return f, true, nil
+	}
+
+	switch name {
+	case CACHED_EXECUTION:
+		f, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("invalid statistic value %q: %w", fields[0], err)
+		}
+		s.CachedExecution = f != 0
+		return f, true, nil
+	case INTERNAL_EXECUTION_TIME:
+		f, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("invalid statistic value %q: %w", fields[0], err)
+		}
+		unit := "milliseconds"
+		if len(fields) > 1 {
+			unit = fields[1]
+		}
+		d, ok := durationOf(f, unit)
+		if !ok {
+			// keep the raw value rather than guess the unit
+			break
+		}
+		s.InternalExecutionTime = d
+		return f, true, nil
+	}
+
+	if s.Unknown == nil {
+		s.Unknown = make(map[string]string)
+	}
+	s.Unknown[name] = value
+	f, ok := leadingFloat(value)
+	return f, ok, nil
+}
+
+// leadingFloat parses the first whitespace separated field of s as a number.
+func leadingFloat(s string) (float64, bool) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(fields[0], 64)
+	return f, err == nil
+}
+
+// durationOf converts a value expressed in the given unit into a time.Duration.
+// It reports false for an unknown unit.
+func durationOf(value float64, unit string) (time.Duration, bool) {
+	var scale time.Duration
+	switch unit {
+	case "nanoseconds", "ns":
+		scale = time.Nanosecond
+	case "microseconds", "us", "µs":
+		scale = time.Microsecond
+	case "milliseconds", "ms":
+		scale = time.Millisecond
+	case "seconds", "s":
+		scale = time.Second
+	default:
+		return 0, false
+	}
+	return time.Duration(value * float64(scale)), true
+}
+
+// Add accumulates other into s. Counters and execution times are summed,
+// CachedExecution is true if any of the summed queries was cached, and
+// unknown statistics are summed when both values are numeric.
+func (s *QueryStatistics) Add(other QueryStatistics) {
+	s.LabelsAdded += other.LabelsAdded
+	s.LabelsRemoved += other.LabelsRemoved
+	s.NodesCreated += other.NodesCreated
+	s.NodesDeleted += other.NodesDeleted
+	s.PropertiesSet += other.PropertiesSet
+	s.PropertiesRemoved += other.PropertiesRemoved
+	s.RelationshipsCreated += other.RelationshipsCreated
+	s.RelationshipsDeleted += other.RelationshipsDeleted
+	s.IndicesCreated += other.IndicesCreated
+	s.IndicesDeleted += other.IndicesDeleted
+	s.ConstraintsCreated += other.ConstraintsCreated
+	s.ConstraintsDeleted += other.ConstraintsDeleted
+	s.CachedExecution = s.CachedExecution || other.CachedExecution
+	s.InternalExecutionTime += other.InternalExecutionTime
+
+	for name, value := range other.Unknown {
+		if s.Unknown == nil {
+			s.Unknown = make(map[string]string)
+		}
+		prev, ok := s.Unknown[name]
+		if !ok {
+			s.Unknown[name] = value
+			continue
+		}
+		a, okA := leadingFloat(prev)
+		b, okB := leadingFloat(value)
+		if okA && okB {
+			s.Unknown[name] = strconv.FormatFloat(a+b, 'f', -1, 64)
+		} else {
+			s.Unknown[name] = value
+		}
+	}
+}
+
+// SumStatistics returns the sum of the statistics of all results, e.g. the
+// results of a Pipeline. Nil results are skipped.
+func SumStatistics(results []*QueryResult) QueryStatistics {
+	var total QueryStatistics
+	for _, r := range results {
+		if r != nil {
+			total.Add(r.stats)
+		}
+	}
+	return total
+}
@@ -0,0 +1,86 @@
+package graph
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func statsResult(t *testing.T, stats ...interface{}) *QueryResult {
+	t.Helper()
+	qr, err := QueryResultNew(&Graph{}, []interface{}{stats})
+	require.NoError(t, err)
+	return qr
+}
+
+func TestParseTypedStatistics(t *testing.T) {
+	qr := statsResult(t,
+		"Labels added: 1",
+		"Labels removed: 2",
+		"Nodes created: 3",
+		"Nodes deleted: 4",
+		"Properties set: 5",
+		"Properties removed: 6",
+		"Relationships created: 7",
+		"Relationships deleted: 8",
+		"Indices created: 9",
+		"Indices deleted: 10",
+		"Constraints created: 11",
+		"Constraints deleted: 12",
+		"Cached execution: 1",
+		"Query internal execution time: 1.5 milliseconds",
+		"Future statistic: not a number",
+	)
+
+	stats := qr.Stats()
+	assert.Equal(t, QueryStatistics{
+		LabelsAdded:           1,
+		LabelsRemoved:         2,
+		NodesCreated:          3,
+		NodesDeleted:          4,
+		PropertiesSet:         5,
+		PropertiesRemoved:     6,
+		RelationshipsCreated:  7,
+		RelationshipsDeleted:  8,
+		IndicesCreated:        9,
+		IndicesDeleted:        10,
+		ConstraintsCreated:    11,
+		ConstraintsDeleted:    12,
+		CachedExecution:       true,
+		InternalExecutionTime: 1500 * time.Microsecond,
+		Unknown:               map[string]string{"Future statistic": "not a number"},
+	}, stats)
+
+	assert.Equal(t, 2, qr.LabelsRemoved())
+	assert.Equal(t, 6, qr.PropertiesRemoved())
+	assert.Equal(t, 11, qr.ConstraintsCreated())
+	assert.Equal(t, 12, qr.ConstraintsDeleted())
+	assert.Equal(t, 1.5, qr.InternalExecutionTime())
+	_, ok := qr.Statistics()["Future statistic"]
+	assert.False(t, ok, "non-numeric statistics are not part of the numeric map")
+}
+
+func TestParseStatisticsErrors(t *testing.T) {
+	_, err := QueryResultNew(&Graph{}, []interface{}{[]interface{}{"Nodes created: many"}})
+	assert.ErrorContains(t, err, "invalid statistic value")
+}
+
+func TestParseStatisticsUnknownUnit(t *testing.T) {
+	qr := statsResult(t, "Query internal execution time: 1.5 fortnights")
+	assert.Equal(t, 1.5, qr.InternalExecutionTime())
+	assert.Zero(t, qr.Stats().InternalExecutionTime)
+	assert.Equal(t, map[string]string{INTERNAL_EXECUTION_TIME: "1.5 fortnights"}, qr.Stats().Unknown)
+}
+
+func TestSumStatistics(t *testing.T) {
+	a := statsResult(t, "Nodes created: 2", "Cached execution: 0", "Query internal execution time: 1 milliseconds", "Custom: 1")
+	b := statsResult(t, "Nodes created: 3", "Cached execution: 1", "Query internal execution time: 2 milliseconds", "Custom: 2")
+
+	total := SumStatistics([]*QueryResult{a, nil, b})
+	assert.Equal(t, 5, total.NodesCreated)
+	assert.True(t, total.CachedExecution)
+	assert.Equal(t, 3*time.Millisecond, total.InternalExecutionTime)
+	assert.Equal(t, "3", total.Unknown["Custom"])
+}
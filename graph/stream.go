@@ -0,0 +1,355 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/snowmerak/falkordb-go/domain"
+)
+
+// RowError reports a record of a streamed result that could not be decoded.
+type RowError struct {
+	Row int
+	Err error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// ResultStream iterates over the records of a query result as they are read
+// from the connection. Each record is decoded when Next is called and the raw
+// reply is discarded afterwards, so memory usage does not grow with the
+// number of records. Label, relationship type and property names are looked
+// up lazily as records reference them. A ResultStream must be closed.
+type ResultStream struct {
+	qr      *QueryResult
+	ctx     context.Context
+	rd      *respReader
+	conn    net.Conn
+	stop    func() bool
+	timeout time.Duration
+
+	abort     context.Context
+	stopAbort func() bool
+	leave     func()
+
+	total   int
+	row     int
+	current *domain.Record
+	done    bool
+	err     error
+}
+
+// QueryStream executes a query and returns a stream over its records.
+// The stream uses a dedicated connection, which is closed by Close.
+func (g *Graph) QueryStream(ctx context.Context, query string, params map[string]interface{}, options *QueryOptions) (*ResultStream, error) {
+	return g.stream(ctx, CmdQuery, query, params, options)
+}
+
+// ROQueryStream executes a read only query and returns a stream over its records.
+func (g *Graph) ROQueryStream(ctx context.Context, query string, params map[string]interface{}, options *QueryOptions) (*ResultStream, error) {
+	return g.stream(ctx, CmdROQuery, query, params, options)
+}
+
+func (g *Graph) stream(ctx context.Context, command string, query string, params map[string]interface{}, options *QueryOptions) (*ResultStream, error) {
+	if g.ReadOnly() && command != CmdROQuery {
+		return nil, ErrReadOnly
+	}
+	args, err := g.commandArgs(command, query, params, options)
+	if err != nil {
+		return nil, err
+	}
+
+	abort, err := g.enter()
+	if err != nil {
+		return nil, err
+	}
+	client, err := g.streamClient(ctx)
+	if err != nil {
+		g.leave()
+		return nil, err
+	}
+	opt := client.Options()
+
+	conn, err := opt.Dialer(ctx, opt.Network, opt.Addr)
+	if err != nil {
+		g.leave()
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	s := newResultStream(ctx, g, conn, opt.ReadTimeout)
+	s.track(abort)
+	if err := s.handshake(ctx, opt); err != nil {
+		s.Close()
+		return nil, s.readError(err)
+	}
+	if err := s.start(args); err != nil {
+		s.Close()
+		return nil, s.readError(err)
+	}
+	return s, nil
+}
+
+func newResultStream(ctx context.Context, g *Graph, conn net.Conn, timeout time.Duration) *ResultStream {
+	return &ResultStream{
+		qr: &QueryResult{
+			graph: g,
+			header: QueryResultHeader{
+				column_names: make([]string, 0),
+				column_types: make([]ResultSetColumnTypes, 0),
+			},
+			currentRecordIdx: -1,
+		},
+		ctx:     ctx,
+		rd:      newRESPReader(conn),
+		conn:    conn,
+		stop:    context.AfterFunc(ctx, func() { _ = conn.Close() }),
+		timeout: timeout,
+	}
+}
+
+// track keeps the stream entered in the graph's tracker until it is closed,
+// and closes its connection when the tracker aborts it.
+func (s *ResultStream) track(abort context.Context) {
+	s.leave = s.qr.graph.leave
+	if abort != nil {
+		conn := s.conn
+		s.abort = abort
+		s.stopAbort = context.AfterFunc(abort, func() { _ = conn.Close() })
+	}
+}
+
+// streamClient returns the client owning the graph key.
+func (g *Graph) streamClient(ctx context.Context) (*redis.Client, error) {
+	switch c := g.Conn.(type) {
+	case *redis.Client:
+		return c, nil
+	case *redis.ClusterClient:
+		return c.MasterForKey(ctx, g.Id)
+	default:
+		return nil, fmt.Errorf("streaming is not supported on %T", g.Conn)
+	}
+}
+
+// handshake authenticates and selects the database on the dedicated connection.
+func (s *ResultStream) handshake(ctx context.Context, opt *redis.Options) error {
+	username, password := opt.Username, opt.Password
+	if opt.CredentialsProviderContext != nil {
+		var err error
+		if username, password, err = opt.CredentialsProviderContext(ctx); err != nil {
+			return err
+		}
+	} else if opt.CredentialsProvider != nil {
+		username, password = opt.CredentialsProvider()
+	}
+
+	var cmds [][]interface{}
+	if password != "" {
+		if username != "" {
+			cmds = append(cmds, []interface{}{"AUTH", username, password})
+		} else {
+			cmds = append(cmds, []interface{}{"AUTH", password})
+		}
+	}
+	if opt.DB > 0 {
+		cmds = append(cmds, []interface{}{"SELECT", opt.DB})
+	}
+	if len(cmds) == 0 {
+		return nil
+	}
+
+	var buf []byte
+	for _, cmd := range cmds {
+		buf = appendCommand(buf, cmd...)
+	}
+	if _, err := s.conn.Write(buf); err != nil {
+		return err
+	}
+	for range cmds {
+		s.setReadDeadline()
+		v, err := s.rd.readValue()
+		if err != nil {
+			return err
+		}
+		if rerr, ok := v.(respError); ok {
+			return rerr
+		}
+	}
+	return nil
+}
+
+// start sends the query and reads the reply up to the first record.
+func (s *ResultStream) start(args []interface{}) error {
+	if _, err := s.conn.Write(appendCommand(nil, args...)); err != nil {
+		return err
+	}
+
+	s.setReadDeadline()
+	n, err := s.rd.readArrayLen()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case n == 1:
+		s.done = true
+		return s.readStatistics()
+	case n >= 3:
+		header, err := s.rd.readValue()
+		if err != nil {
+			return err
+		}
+		if rerr, ok := header.(respError); ok {
+			return rerr
+		}
+		if err := s.qr.parseHeader(header); err != nil {
+			return err
+		}
+		s.total, err = s.rd.readArrayLen()
+		if err != nil {
+			return err
+		}
+		if s.total == 0 {
+			s.done = true
+			return s.readStatistics()
+		}
+		return nil
+	default:
+		return fmt.Errorf("unexpected response length %d", n)
+	}
+}
+
+func (s *ResultStream) readStatistics() error {
+	s.setReadDeadline()
+	stats, err := s.rd.readValue()
+	if err != nil {
+		return err
+	}
+	if rerr, ok := stats.(respError); ok {
+		return rerr
+	}
+	return s.qr.parseStatistics(stats)
+}
+
+func (s *ResultStream) setReadDeadline() {
+	if s.timeout > 0 && s.conn != nil {
+		_ = s.conn.SetReadDeadline(time.Now().Add(s.timeout))
+	}
+}
+
+// Next advances to the next record. It returns false when the stream is
+// exhausted or an error occurred; check Err afterwards.
+func (s *ResultStream) Next() bool {
+	s.current = nil
+	if s.done || s.err != nil {
+		return false
+	}
+
+	s.setReadDeadline()
+	raw, err := s.rd.readValue()
+	if err != nil {
+		s.err = s.readError(err)
+		return false
+	}
+	if rerr, ok := raw.(respError); ok {
+		s.err = &RowError{Row: s.row, Err: rerr}
+		return false
+	}
+	record, err := s.qr.parseRecord(s.row, raw)
+	if err != nil {
+		s.err = &RowError{Row: s.row, Err: err}
+		return false
+	}
+	s.current = record
+	s.row++
+
+	if s.row == s.total {
+		s.done = true
+		if err := s.readStatistics(); err != nil {
+			s.err = s.readError(err)
+		}
+	}
+	return true
+}
+
+// readError prefers the context error when a read failed because the
+// context was cancelled and the connection closed underneath it, and reports
+// redis.ErrClosed when the client closed it on shutdown.
+func (s *ResultStream) readError(err error) error {
+	if ctxErr := s.ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	if s.abort != nil && s.abort.Err() != nil {
+		return redis.ErrClosed
+	}
+	return err
+}
+
+// Record returns the current record.
+func (s *ResultStream) Record() *domain.Record {
+	return s.current
+}
+
+// Err returns the error that stopped the iteration, if any. Records that
+// fail to decode are reported as a *RowError.
+func (s *ResultStream) Err() error {
+	return s.err
+}
+
+// Columns returns the column names of the result.
+func (s *ResultStream) Columns() []string {
+	return s.qr.header.column_names
+}
+
+// Header returns the parsed result header metadata.
+func (s *ResultStream) Header() QueryResultHeader {
+	return s.qr.header
+}
+
+// Stats returns the typed query statistics. They are available once the
+// stream has been consumed.
+func (s *ResultStream) Stats() QueryStatistics {
+	return s.qr.stats
+}
+
+// Statistics returns the numeric query statistics once the stream has been consumed.
+func (s *ResultStream) Statistics() map[string]float64 {
+	return s.qr.statistics
+}
+
+// Close releases the dedicated connection. Closing a stream before it has
+// been consumed discards the remaining records.
+func (s *ResultStream) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	if s.stop != nil {
+		s.stop()
+	}
+	if s.stopAbort != nil {
+		s.stopAbort()
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	if s.leave != nil {
+		s.leave()
+	}
+	if errors.Is(err, net.ErrClosed) || errors.Is(err, io.EOF) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,127 @@
+package graph
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/snowmerak/falkordb-go/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveRESP answers a single command on conn with the given raw reply.
+func serveRESP(t *testing.T, conn net.Conn, reply string) {
+	t.Helper()
+	go func() {
+		rd := newRESPReader(conn)
+		if _, err := rd.readValue(); err != nil {
+			return
+		}
+		_, _ = conn.Write([]byte(reply))
+	}()
+}
+
+func startStream(t *testing.T, reply string) *ResultStream {
+	t.Helper()
+	g := NewGraphWithSchema(GraphSchemaWithData([]string{"Person"}, nil, []string{"name"}))
+	client, server := net.Pipe()
+	t.Cleanup(func() { server.Close() })
+	serveRESP(t, server, reply)
+
+	s := newResultStream(context.Background(), g, client, 0)
+	require.NoError(t, s.start(testArgs(t, g, CmdQuery, "MATCH (n) RETURN n", nil)))
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func resp(lines ...string) string {
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
+
+func TestResultStreamRecords(t *testing.T) {
+	s := startStream(t, resp(
+		"*3",
+		"*2", "*2", ":1", "$1", "x", "*2", ":2", "$1", "n",
+		"*2",
+		"*2", "*2", ":3", ":1", "*3", ":9", "*1", ":0", "*1", "*3", ":0", ":2", "$4", "John",
+		"*2", "*2", ":3", ":2", "*3", ":10", "*0", "*0",
+		"*2", "+Nodes created: 0", "$48", "Query internal execution time: 0.10 milliseconds",
+	))
+
+	assert.Equal(t, []string{"x", "n"}, s.Columns())
+
+	require.True(t, s.Next())
+	assert.Equal(t, int64(1), s.Record().GetByIndex(0))
+	n := s.Record().GetByIndex(1).(*domain.Node)
+	assert.Equal(t, uint64(9), n.ID)
+	assert.Equal(t, []string{"Person"}, n.Labels)
+	assert.Equal(t, "John", n.GetProperty("name"))
+
+	require.True(t, s.Next())
+	assert.Equal(t, int64(2), s.Record().GetByIndex(0))
+
+	assert.False(t, s.Next())
+	assert.NoError(t, s.Err())
+	assert.Equal(t, 0.1, s.Statistics()[INTERNAL_EXECUTION_TIME])
+}
+
+func TestResultStreamStatisticsOnly(t *testing.T) {
+	s := startStream(t, resp("*1", "*1", "+Nodes created: 3"))
+	assert.False(t, s.Next())
+	assert.NoError(t, s.Err())
+	assert.Equal(t, 3, s.Stats().NodesCreated)
+}
+
+func TestResultStreamRowError(t *testing.T) {
+	s := startStream(t, resp(
+		"*3",
+		"*1", "*2", ":1", "$1", "x",
+		"*2",
+		"*1", "*2", ":3", ":1",
+		"*1", "*2", ":3", "$4", "oops",
+		"*0",
+	))
+
+	require.True(t, s.Next())
+	assert.False(t, s.Next())
+	var rowErr *RowError
+	require.ErrorAs(t, s.Err(), &rowErr)
+	assert.Equal(t, 1, rowErr.Row)
+	assert.ErrorContains(t, rowErr, "integer scalar not int64")
+}
+
+func TestResultStreamServerError(t *testing.T) {
+	g := NewGraphWithSchema(GraphSchemaWithData(nil, nil, nil))
+	client, server := net.Pipe()
+	defer server.Close()
+	serveRESP(t, server, resp("-ERR Invalid graph operation on empty key"))
+
+	s := newResultStream(context.Background(), g, client, 0)
+	defer s.Close()
+	err := s.start(testArgs(t, g, CmdROQuery, "RETURN 1", nil))
+	assert.EqualError(t, err, "ERR Invalid graph operation on empty key")
+}
+
+func TestRESPReaderBulk(t *testing.T) {
+	rd := newRESPReader(bufio.NewReader(strings.NewReader(resp("$5", "a\r\nbc", "$-1", "*-1"))))
+
+	v, err := rd.readValue()
+	require.NoError(t, err)
+	assert.Equal(t, "a\r\nbc", v)
+
+	v, err = rd.readValue()
+	require.NoError(t, err)
+	assert.Nil(t, v)
+
+	v, err = rd.readValue()
+	require.NoError(t, err)
+	assert.Nil(t, v)
+}
+
+func TestAppendCommand(t *testing.T) {
+	got := string(appendCommand(nil, "GRAPH.QUERY", "g", 42))
+	assert.Equal(t, resp("*3", "$11", "GRAPH.QUERY", "$1", "g", "$2", "42"), got)
+}
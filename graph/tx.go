@@ -0,0 +1,209 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultTxMaxRetries = 3
+	defaultTxBackoff    = 10 * time.Millisecond
+)
+
+// TxOptions configures Graph.Tx.
+type TxOptions struct {
+	// Watch watches the graph key before the callback runs, so that the
+	// transaction is aborted and retried when the graph is modified by
+	// another client before EXEC.
+	Watch bool
+	// MaxRetries is the number of retries after a watch conflict (default
+	// 3). A negative value disables retries.
+	MaxRetries int
+	// Backoff is the delay before the first retry, doubled for every
+	// following retry (default 10ms). Every delay is randomly shortened by
+	// up to half, so that conflicting clients do not retry in lockstep.
+	Backoff time.Duration
+}
+
+func (o *TxOptions) withDefaults() TxOptions {
+	var out TxOptions
+	if o != nil {
+		out = *o
+	}
+	switch {
+	case out.MaxRetries == 0:
+		out.MaxRetries = defaultTxMaxRetries
+	case out.MaxRetries < 0:
+		out.MaxRetries = 0
+	}
+	if out.Backoff <= 0 {
+		out.Backoff = defaultTxBackoff
+	}
+	return out
+}
+
+// GraphTx queues the statements of a transaction.
+type GraphTx struct {
+	g    *Graph
+	conn interface {
+		Do(ctx context.Context, args ...interface{}) *redis.Cmd
+	}
+	stmts []QueryRequest
+}
+
+// Query queues a query.
+func (tx *GraphTx) Query(query string, params map[string]interface{}, options *QueryOptions) {
+	tx.stmts = append(tx.stmts, QueryRequest{Command: CmdQuery, Query: query, Params: params, Options: options})
+}
+
+// ROQuery queues a read only query.
+func (tx *GraphTx) ROQuery(query string, params map[string]interface{}, options *QueryOptions) {
+	tx.stmts = append(tx.stmts, QueryRequest{Command: CmdROQuery, Query: query, Params: params, Options: options})
+}
+
+// Read executes a read only query immediately, before the queued statements.
+// With TxOptions.Watch it runs on the watching connection, so the transaction
+// is retried if the graph changes after the read.
+func (tx *GraphTx) Read(ctx context.Context, query string, params map[string]interface{}) (*QueryResult, error) {
+	args, err := tx.g.commandArgs(CmdROQuery, query, params, nil)
+	if err != nil {
+		return nil, err
+	}
+	r, err := tx.conn.Do(ctx, args...).Result()
+	if err != nil {
+		return nil, err
+	}
+	return QueryResultNew(tx.g, r)
+}
+
+// TxResult holds the outcome of every statement of a transaction.
+type TxResult struct {
+	// Results holds the result of each statement, or nil where it failed.
+	Results []*QueryResult
+	// Errors holds the error of each statement, or nil where it succeeded.
+	Errors []error
+	// Attempts is the number of times the transaction was run.
+	Attempts int
+}
+
+// Err returns the first statement error, if any.
+func (r *TxResult) Err() error {
+	for i, err := range r.Errors {
+		if err != nil {
+			return fmt.Errorf("statement %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Tx runs the statements queued by fn atomically inside MULTI/EXEC. FalkorDB
+// does not roll back: a statement that fails does not undo the others, and
+// the errors are reported per statement in the result and by TxResult.Err.
+// If fn returns an error nothing is sent. fn is called again on every retry.
+// Watch conflicts are retried up to 3 times unless opts set MaxRetries.
+// Read-only graphs are rejected with ErrReadOnly.
+func (g *Graph) Tx(ctx context.Context, fn func(tx *GraphTx) error, opts *TxOptions) (*TxResult, error) {
+	if g.ReadOnly() {
+		return nil, ErrReadOnly
+	}
+	if _, err := g.enter(); err != nil {
+		return nil, err
+	}
+	defer g.leave()
+
+	o := opts.withDefaults()
+
+	backoff := o.Backoff
+	for attempt := 1; ; attempt++ {
+		res, err := g.runTx(ctx, fn, o.Watch)
+		if errors.Is(err, redis.TxFailedErr) && attempt <= o.MaxRetries {
+			t := time.NewTimer(txDelay(backoff))
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return nil, ctx.Err()
+			case <-t.C:
+			}
+			backoff *= 2
+			continue
+		}
+		if res != nil {
+			res.Attempts = attempt
+			if err == nil {
+				err = res.Err()
+			}
+		}
+		return res, err
+	}
+}
+
+// txDelay shortens backoff by a random amount of up to half of it.
+func txDelay(backoff time.Duration) time.Duration {
+	return backoff - time.Duration(rand.Int63n(int64(backoff)/2+1))
+}
+
+func (g *Graph) runTx(ctx context.Context, fn func(tx *GraphTx) error, watch bool) (*TxResult, error) {
+	if !watch {
+		tx := &GraphTx{g: g, conn: g.Conn}
+		if err := fn(tx); err != nil {
+			return nil, err
+		}
+		return g.execTx(ctx, g.Conn.TxPipeline(), tx.stmts)
+	}
+
+	var res *TxResult
+	err := g.Conn.Watch(ctx, func(rtx *redis.Tx) error {
+		tx := &GraphTx{g: g, conn: rtx}
+		if err := fn(tx); err != nil {
+			return err
+		}
+		var err error
+		res, err = g.execTx(ctx, rtx.TxPipeline(), tx.stmts)
+		return err
+	}, g.Id)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// execTx sends the statements in a MULTI/EXEC block and collects the result
+// or error of each. Only a failure of the transaction itself is returned.
+func (g *Graph) execTx(ctx context.Context, pipe redis.Pipeliner, stmts []QueryRequest) (*TxResult, error) {
+	res := &TxResult{
+		Results: make([]*QueryResult, len(stmts)),
+		Errors:  make([]error, len(stmts)),
+	}
+	if len(stmts) == 0 {
+		return res, nil
+	}
+
+	cmds := make([]*redis.Cmd, len(stmts))
+	for i, s := range stmts {
+		args, err := g.commandArgs(s.Command, s.Query, s.Params, s.Options)
+		if err != nil {
+			return nil, fmt.Errorf("statement %d: %w", i, err)
+		}
+		cmds[i] = pipe.Do(ctx, args...)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		var rerr redis.Error
+		if !errors.As(err, &rerr) || errors.Is(err, redis.TxFailedErr) {
+			return nil, err
+		}
+	}
+
+	for i, cmd := range cmds {
+		if err := cmd.Err(); err != nil {
+			res.Errors[i] = err
+			continue
+		}
+		res.Results[i], res.Errors[i] = QueryResultNew(g, cmd.Val())
+	}
+	return res, nil
+}
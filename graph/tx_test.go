@@ -0,0 +1,72 @@
+package graph
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxRejectsReadOnly(t *testing.T) {
+	g := NewWithMode("g", nil, true)
+	called := false
+	_, err := g.Tx(context.Background(), func(tx *GraphTx) error {
+		called = true
+		return nil
+	}, nil)
+	assert.ErrorIs(t, err, ErrReadOnly)
+	assert.False(t, called)
+}
+
+func TestTxResultErr(t *testing.T) {
+	res := &TxResult{Errors: []error{nil, nil}}
+	assert.NoError(t, res.Err())
+
+	res.Errors[1] = assert.AnError
+	assert.EqualError(t, res.Err(), "statement 1: "+assert.AnError.Error())
+	assert.ErrorIs(t, res.Err(), assert.AnError)
+}
+
+func TestTxDelay(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		d := txDelay(10 * time.Millisecond)
+		assert.GreaterOrEqual(t, d, 5*time.Millisecond)
+		assert.LessOrEqual(t, d, 10*time.Millisecond)
+	}
+}
+
+func TestTxOptionsDefaults(t *testing.T) {
+	var nilOpts *TxOptions
+	assert.Equal(t, TxOptions{MaxRetries: 3, Backoff: 10 * time.Millisecond}, nilOpts.withDefaults())
+	assert.Equal(t, TxOptions{Watch: true, MaxRetries: 3, Backoff: 10 * time.Millisecond}, (&TxOptions{Watch: true}).withDefaults())
+	assert.Equal(t, 0, (&TxOptions{MaxRetries: -1}).withDefaults().MaxRetries)
+	assert.Equal(t, 5, (&TxOptions{MaxRetries: 5}).withDefaults().MaxRetries)
+}
+
+func TestTxRetriesConflicts(t *testing.T) {
+	conflicts := 0
+	conn := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1"})
+	conn.AddHook(replyHook{reply: func(args []interface{}) (interface{}, error) {
+		if args[0] == "exec" {
+			conflicts++
+		}
+		return nil, redis.TxFailedErr
+	}})
+	defer conn.Close()
+	g := New("g", conn)
+	queue := func(tx *GraphTx) error {
+		tx.Query("CREATE ()", nil, nil)
+		return nil
+	}
+
+	_, err := g.Tx(context.Background(), queue, &TxOptions{Watch: false, Backoff: time.Microsecond})
+	assert.ErrorIs(t, err, redis.TxFailedErr)
+	assert.Equal(t, 4, conflicts, "zero MaxRetries retries 3 times")
+
+	conflicts = 0
+	_, err = g.Tx(context.Background(), queue, &TxOptions{MaxRetries: -1})
+	assert.ErrorIs(t, err, redis.TxFailedErr)
+	assert.Equal(t, 1, conflicts)
+}
@@ -0,0 +1,137 @@
+package falkordb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/snowmerak/falkordb-go/graph"
+)
+
+// healthGraph is the graph key the health query runs against. It is never
+// created, as read only queries do not create graphs.
+const healthGraph = "__falkordb_health__"
+
+// NodeHealth reports on one node of a cluster.
+type NodeHealth struct {
+	Addr string
+	// Reachable reports whether the node answered PING.
+	Reachable bool
+	// Role is the replication role of the node, "master" or "slave".
+	Role string
+	// ModuleVersion is the version of the graph module, or 0 when it is not
+	// loaded.
+	ModuleVersion int
+	// Latency is the round trip time of PING.
+	Latency time.Duration
+	// Err is the first error the node returned.
+	Err error
+}
+
+// Health reports on the deployment a FalkorDB client is connected to.
+type Health struct {
+	// ModuleVersion is the version of the graph module, or 0 when it is not
+	// loaded.
+	ModuleVersion int
+	// Role is the replication role of the server, "master" or "slave". It is
+	// empty on clusters, see Nodes.
+	Role string
+	// ClusterState is the cluster_state of CLUSTER INFO, "ok" or "fail". It is
+	// empty when not connected to a cluster.
+	ClusterState string
+	// Nodes holds every master and replica of a cluster.
+	Nodes []NodeHealth
+	// QueryLatency is the round trip time of a trivial GRAPH.RO_QUERY.
+	QueryLatency time.Duration
+}
+
+// Ping checks that the server answers and has the graph module loaded.
+func (db *FalkorDB) Ping(ctx context.Context) error {
+	if err := db.Conn.Ping(ctx).Err(); err != nil {
+		return err
+	}
+	_, err := graph.ServerModuleVersion(ctx, db.Conn)
+	return err
+}
+
+// Health inspects the deployment. The report is filled in as far as the
+// servers answered, and an error explains why the deployment is not ready:
+// the graph module is missing, the cluster state is not ok, a cluster node is
+// unreachable or the health query failed.
+func (db *FalkorDB) Health(ctx context.Context) (*Health, error) {
+	h := &Health{}
+	var problems []string
+
+	version, err := graph.ServerModuleVersion(ctx, db.Conn)
+	if err != nil {
+		return h, err
+	}
+	h.ModuleVersion = version
+
+	if cc, ok := db.Conn.(*redis.ClusterClient); ok {
+		info, err := cc.ClusterInfo(ctx).Result()
+		if err != nil {
+			return h, err
+		}
+		h.ClusterState = parseInfo(info)["cluster_state"]
+		if h.ClusterState != "ok" {
+			problems = append(problems, "cluster state is "+h.ClusterState)
+		}
+
+		h.Nodes = clusterHealth(ctx, cc)
+		for _, n := range h.Nodes {
+			if n.Err != nil {
+				problems = append(problems, fmt.Sprintf("node %s: %v", n.Addr, n.Err))
+			}
+		}
+	} else {
+		info, err := db.Conn.Info(ctx, "replication").Result()
+		if err != nil {
+			return h, err
+		}
+		h.Role = parseInfo(info)["role"]
+	}
+
+	start := time.Now()
+	err = db.Conn.Do(ctx, graph.CmdROQuery, healthGraph, "RETURN 1").Err()
+	h.QueryLatency = time.Since(start)
+	if err != nil && !strings.Contains(err.Error(), "empty key") {
+		problems = append(problems, "health query: "+err.Error())
+	}
+
+	if len(problems) > 0 {
+		return h, errors.New("not ready: " + strings.Join(problems, "; "))
+	}
+	return h, nil
+}
+
+// clusterHealth checks every master and replica of a cluster.
+func clusterHealth(ctx context.Context, cc *redis.ClusterClient) []NodeHealth {
+	var mu sync.Mutex
+	var nodes []NodeHealth
+	_ = cc.ForEachShard(ctx, func(ctx context.Context, client *redis.Client) error {
+		n := NodeHealth{Addr: client.Options().Addr}
+		start := time.Now()
+		if n.Err = client.Ping(ctx).Err(); n.Err == nil {
+			n.Reachable = true
+			n.Latency = time.Since(start)
+			var info string
+			if info, n.Err = client.Info(ctx, "replication").Result(); n.Err == nil {
+				n.Role = parseInfo(info)["role"]
+				n.ModuleVersion, n.Err = graph.ServerModuleVersion(ctx, client)
+			}
+		}
+		mu.Lock()
+		nodes = append(nodes, n)
+		mu.Unlock()
+		return nil
+	})
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Addr < nodes[j].Addr })
+	return nodes
+}
@@ -1,6 +1,7 @@
 package integration_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/snowmerak/falkordb-go/domain"
@@ -167,9 +168,32 @@ func TestPoint(t *testing.T) {
 	}
 	res.Next()
 	r := res.Record()
-	point := r.GetByIndex(0).(map[string]interface{})
-	assert.Equal(t, point["latitude"], 37.0, "Unexpected latitude value")
-	assert.Equal(t, point["longitude"], -122.0, "Unexpected longitude value")
+	point := r.GetByIndex(0).(domain.Point)
+	assert.Equal(t, point.Latitude, 37.0, "Unexpected latitude value")
+	assert.Equal(t, point.Longitude, -122.0, "Unexpected longitude value")
+
+	res, err = graphInstance.Query("RETURN $p", map[string]interface{}{"p": domain.NewPoint(40.5, -73.25)}, nil)
+	assert.Nil(t, err)
+	res.Next()
+	assert.Equal(t, domain.NewPoint(40.5, -73.25), res.Record().GetByIndex(0))
+}
+
+func TestNodesWithinDistance(t *testing.T) {
+	g := db.SelectGraph("stores")
+	g.Delete()
+	defer g.Delete()
+
+	_, err := g.Query(`CREATE (:Store {name: 'near', loc: point({latitude: 37.001, longitude: -122.0})}),
+		(:Store {name: 'nearest', loc: point({latitude: 37.0, longitude: -122.0})}),
+		(:Store {name: 'far', loc: point({latitude: 38.0, longitude: -122.0})})`, nil, nil)
+	assert.Nil(t, err)
+
+	nodes, err := g.NodesWithinDistance(context.Background(), "Store", "loc", domain.NewPoint(37.0, -122.0), 1000)
+	assert.Nil(t, err)
+	if assert.Len(t, nodes, 2) {
+		assert.Equal(t, "nearest", nodes[0].GetProperty("name"))
+		assert.Equal(t, "near", nodes[1].GetProperty("name"))
+	}
 }
 
 func TestVectorF32(t *testing.T) {
@@ -180,6 +204,12 @@ func TestVectorF32(t *testing.T) {
 	}
 	res.Next()
 	r := res.Record()
-	vec := r.GetByIndex(0).([]float32)
-	assert.Equal(t, vec, []float32{1.0, 2.0, 3.0}, "Unexpected vector value")
+	vec := r.GetByIndex(0).(domain.Vector)
+	assert.Equal(t, vec, domain.Vector{1.0, 2.0, 3.0}, "Unexpected vector value")
+
+	want := domain.Vector{0.1, -2.5e-7, 3.4028235e38}
+	res, err = graphInstance.Query("RETURN $v", map[string]interface{}{"v": want}, nil)
+	assert.Nil(t, err)
+	res.Next()
+	assert.Equal(t, want, res.Record().GetByIndex(0), "vector parameter should round trip exactly")
 }
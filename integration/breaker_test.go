@@ -0,0 +1,108 @@
+package integration_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	falkordb "github.com/snowmerak/falkordb-go"
+	"github.com/snowmerak/falkordb-go/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+	createGraph()
+	defer db.SetBreaker(nil)
+
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	db.SetBreaker(&falkordb.BreakerOptions{
+		PerGraph:     true,
+		MinRequests:  3,
+		FailureRatio: 0.5,
+		OpenTimeout:  time.Second,
+		IsFailure:    func(err error) bool { return strings.Contains(err.Error(), "Invalid input") },
+		Clock:        clock,
+	})
+	g := db.SelectGraph("social")
+
+	for i := 0; i < 3; i++ {
+		_, err := g.ROQuery("RETURN 1 +", nil, nil)
+		require.Error(t, err)
+	}
+	assert.Equal(t, falkordb.BreakerOpen, db.BreakerState("social"))
+	assert.Equal(t, falkordb.BreakerClosed, db.BreakerState("other"))
+
+	_, err := g.ROQuery("RETURN 1", nil, nil)
+	assert.ErrorIs(t, err, falkordb.ErrCircuitOpen)
+
+	clock.Advance(500 * time.Millisecond)
+	_, err = g.ROQuery("RETURN 1", nil, nil)
+	assert.ErrorIs(t, err, falkordb.ErrCircuitOpen)
+
+	clock.Advance(time.Second)
+	res, err := g.ROQuery("RETURN 1", nil, nil)
+	require.NoError(t, err)
+	require.True(t, res.Next())
+	assert.Equal(t, falkordb.BreakerClosed, db.BreakerState("social"))
+}
+
+func TestConcurrencyLimiter(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+	createGraph()
+	defer db.SetBreaker(nil)
+
+	db.SetBreaker(&falkordb.BreakerOptions{MaxInFlight: 1})
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	block := func(next graph.QueryFunc) graph.QueryFunc {
+		return func(ctx context.Context, g *graph.Graph, req graph.QueryRequest) (*graph.QueryResult, error) {
+			if req.Query == "RETURN 'slow'" {
+				close(entered)
+				<-release
+			}
+			return next(ctx, g, req)
+		}
+	}
+	g := db.SelectGraphWithDefaults("social", graph.Defaults{Hooks: []graph.QueryHook{block}})
+
+	done := make(chan error)
+	go func() {
+		_, err := g.ROQuery("RETURN 'slow'", nil, nil)
+		done <- err
+	}()
+	<-entered
+
+	_, err := g.ROQuery("RETURN 1", nil, nil)
+	assert.ErrorIs(t, err, falkordb.ErrTooManyInFlight)
+
+	close(release)
+	require.NoError(t, <-done)
+	_, err = g.ROQuery("RETURN 1", nil, nil)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,90 @@
+package integration_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/snowmerak/falkordb-go/bulk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkLoader(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+	users := write("User.csv", "uid:ID(User),name:STRING,age:INT\n1,Ann,31\n2,Bob,forty\n3,Cid,27\n")
+	follows := write("FOLLOWS.csv", ":START_ID(User),:END_ID(User),since\n1,3,2020\n3,1,2021\n1,2,2022\n")
+	rejects := filepath.Join(dir, "rejects.csv")
+	checkpoint := filepath.Join(dir, "load.json")
+
+	g := db.SelectGraph("bulk_loader")
+	g.Delete()
+	defer g.Delete()
+
+	var progress []bulk.Progress
+	loader := bulk.NewLoader(g, bulk.Options{
+		BatchSize:  1,
+		RejectFile: rejects,
+		Checkpoint: checkpoint,
+		Progress:   func(p bulk.Progress) { progress = append(progress, p) },
+	})
+	summary, err := loader.Load(context.Background(),
+		[]bulk.NodeFile{{Path: users}},
+		[]bulk.RelationFile{{Path: follows}},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 2, summary.Nodes)
+	assert.Equal(t, 2, summary.Relations)
+	assert.Equal(t, 2, summary.Rejected)
+	assert.NotEmpty(t, progress)
+
+	res, err := g.ROQuery("MATCH (a:User)-[f:FOLLOWS]->(b:User) RETURN a.name, b.name, f.since ORDER BY f.since", nil, nil)
+	require.NoError(t, err)
+	require.True(t, res.Next())
+	assert.Equal(t, []interface{}{"Ann", "Cid", int64(2020)}, res.Record().Values())
+
+	b, err := os.ReadFile(rejects)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "column age")
+	assert.Contains(t, string(b), `unknown END_ID "2"`)
+
+	// the finished checkpoint turns a rerun into a no-op
+	summary, err = bulk.NewLoader(g, bulk.Options{Checkpoint: checkpoint}).Load(context.Background(),
+		[]bulk.NodeFile{{Path: users}},
+		[]bulk.RelationFile{{Path: follows}},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 2, summary.Nodes)
+}
+
+func TestBulkLoaderQuotedHeaders(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	path := filepath.Join(t.TempDir(), "Person.csv")
+	require.NoError(t, os.WriteFile(path, []byte("uid:ID(Person),first name:STRING,a`b\n1,Ann,x\n"), 0o644))
+
+	g := db.SelectGraph("bulk_loader_quoted")
+	g.Delete()
+	defer g.Delete()
+
+	summary, err := bulk.NewLoader(g, bulk.Options{}).Load(context.Background(), []bulk.NodeFile{{Path: path}}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.Nodes)
+
+	res, err := g.ROQuery("MATCH (p:Person) RETURN p.`first name`, p.`a``b`", nil, nil)
+	require.NoError(t, err)
+	require.True(t, res.Next())
+	assert.Equal(t, []interface{}{"Ann", "x"}, res.Record().Values())
+}
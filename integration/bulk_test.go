@@ -0,0 +1,86 @@
+package integration_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/snowmerak/falkordb-go/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkWrites(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	createGraph()
+	defer createGraph()
+
+	ctx := context.Background()
+	opts := &graph.BulkOptions{ChunkSize: 7, PipelineDepth: 2}
+
+	rows := make([]map[string]interface{}, 50)
+	for i := range rows {
+		rows[i] = map[string]interface{}{"uid": i, "name": "user"}
+	}
+	stats, err := graphInstance.BulkCreateNodes(ctx, "User", rows, opts)
+	require.NoError(t, err)
+	assert.Equal(t, 50, stats.NodesCreated)
+	assert.Equal(t, 100, stats.PropertiesSet)
+
+	merge := []map[string]interface{}{{"uid": 0, "name": "first"}, {"uid": 50, "name": "new"}}
+	stats, err = graphInstance.BulkMergeNodes(ctx, "User", []string{"uid"}, merge, opts)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.NodesCreated)
+
+	edges := make([]graph.BulkEdge, 49)
+	for i := range edges {
+		edges[i] = graph.BulkEdge{
+			From:       map[string]interface{}{"uid": i},
+			To:         map[string]interface{}{"uid": i + 1},
+			Properties: map[string]interface{}{"weight": 1.5},
+		}
+	}
+	endpoint := graph.EdgeEndpoint{Label: "User", Keys: []string{"uid"}}
+	stats, err = graphInstance.BulkCreateEdges(ctx, "FOLLOWS", endpoint, endpoint, edges, opts)
+	require.NoError(t, err)
+	assert.Equal(t, 49, stats.RelationshipsCreated)
+
+	res, err := graphInstance.ROQuery("MATCH (:User {uid: 0})-[e:FOLLOWS]->(u:User) RETURN u.uid, e.weight", nil, nil)
+	require.NoError(t, err)
+	require.True(t, res.Next())
+	assert.Equal(t, int64(1), res.Record().GetByIndex(0))
+	assert.Equal(t, 1.5, res.Record().GetByIndex(1))
+
+	// merging on a null key fails the second chunk only
+	bad := []map[string]interface{}{{"uid": 100}, {"uid": nil}}
+	stats, err = graphInstance.BulkMergeNodes(ctx, "User", []string{"uid"}, bad, &graph.BulkOptions{ChunkSize: 1})
+	var chunkErr *graph.BulkChunkError
+	require.True(t, errors.As(err, &chunkErr))
+	assert.Equal(t, 1, chunkErr.Chunk)
+	assert.Equal(t, 1, chunkErr.Offset)
+	assert.Equal(t, 1, stats.NodesCreated)
+}
+
+func TestBulkWritesQuotedKeys(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	createGraph()
+	defer createGraph()
+
+	rows := []map[string]interface{}{{"first name": "Ann", "a-b": 1, "x}) DETACH DELETE n //": true}}
+	stats, err := graphInstance.BulkCreateNodes(context.Background(), "Quoted", rows, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.NodesCreated)
+	assert.Equal(t, 3, stats.PropertiesSet)
+
+	res, err := graphInstance.ROQuery("MATCH (n:Quoted) RETURN n.`first name`, n.`a-b`", nil, nil)
+	require.NoError(t, err)
+	require.True(t, res.Next())
+	assert.Equal(t, "Ann", res.Record().GetByIndex(0))
+	assert.Equal(t, int64(1), res.Record().GetByIndex(1))
+}
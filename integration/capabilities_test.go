@@ -0,0 +1,35 @@
+package integration_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/snowmerak/falkordb-go/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapabilities(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+	createGraph()
+
+	caps, err := db.Capabilities()
+	require.NoError(t, err)
+	assert.Greater(t, caps.ModuleVersion, 0)
+
+	gcaps, err := graphInstance.Capabilities(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, caps, gcaps)
+
+	err = caps.Require(graph.Feature{Name: "FUTURE", MinVersion: caps.ModuleVersion + 1})
+	assert.True(t, errors.Is(err, graph.ErrUnsupportedByServer))
+	assert.NoError(t, caps.Require(graph.Feature{Name: "PAST", MinVersion: caps.ModuleVersion}))
+
+	if !caps.Supports(graph.FeatureUDF) {
+		err := db.LoadUDF("caps_lib", "function f() { return 1 }")
+		assert.True(t, errors.Is(err, graph.ErrUnsupportedByServer))
+	}
+}
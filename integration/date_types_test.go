@@ -5,7 +5,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/snowmerak/falkordb-go/domain"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDateTypes(t *testing.T) {
@@ -22,13 +24,12 @@ func TestDateTypes(t *testing.T) {
 		{
 			query: "RETURN date('2023-01-01')",
 			check: func(v interface{}) error {
-				ts, ok := v.(time.Time)
+				d, ok := v.(domain.Date)
 				if !ok {
-					return fmt.Errorf("expected time.Time, got %T", v)
+					return fmt.Errorf("expected domain.Date, got %T", v)
 				}
-				// 2023-01-01 UTC = 1672531200
-				if ts.Unix() != 1672531200 {
-					return fmt.Errorf("expected unix 1672531200, got %d", ts.Unix())
+				if d != (domain.Date{Year: 2023, Month: time.January, Day: 1}) {
+					return fmt.Errorf("expected 2023-01-01, got %v", d)
 				}
 				return nil
 			},
@@ -36,13 +37,12 @@ func TestDateTypes(t *testing.T) {
 		{
 			query: "RETURN localdatetime('2023-01-01T12:00:00')",
 			check: func(v interface{}) error {
-				ts, ok := v.(time.Time)
+				dt, ok := v.(domain.LocalDateTime)
 				if !ok {
-					return fmt.Errorf("expected time.Time, got %T", v)
+					return fmt.Errorf("expected domain.LocalDateTime, got %T", v)
 				}
-				// 2023-01-01 12:00:00 UTC = 1672574400
-				if ts.Unix() != 1672574400 {
-					return fmt.Errorf("expected unix 1672574400, got %d", ts.Unix())
+				if dt.String() != "2023-01-01T12:00:00" {
+					return fmt.Errorf("expected 2023-01-01T12:00:00, got %v", dt)
 				}
 				return nil
 			},
@@ -50,13 +50,12 @@ func TestDateTypes(t *testing.T) {
 		{
 			query: "RETURN localtime('12:00:00')",
 			check: func(v interface{}) error {
-				ts, ok := v.(time.Time)
+				lt, ok := v.(domain.LocalTime)
 				if !ok {
-					return fmt.Errorf("expected time.Time, got %T", v)
+					return fmt.Errorf("expected domain.LocalTime, got %T", v)
 				}
-				// 1900-01-01 12:00:00 UTC = -2208945600
-				if ts.Unix() != -2208945600 {
-					return fmt.Errorf("expected unix -2208945600, got %d", ts.Unix())
+				if lt != (domain.LocalTime{Hour: 12}) {
+					return fmt.Errorf("expected 12:00:00, got %v", lt)
 				}
 				return nil
 			},
@@ -64,11 +63,11 @@ func TestDateTypes(t *testing.T) {
 		{
 			query: "RETURN duration({hours: 1})",
 			check: func(v interface{}) error {
-				d, ok := v.(time.Duration)
+				d, ok := v.(domain.Duration)
 				if !ok {
-					return fmt.Errorf("expected time.Duration, got %T", v)
+					return fmt.Errorf("expected domain.Duration, got %T", v)
 				}
-				if d != time.Hour {
+				if td, _ := d.TimeDuration(); td != time.Hour {
 					return fmt.Errorf("expected 1h, got %v", d)
 				}
 				return nil
@@ -94,3 +93,36 @@ func TestDateTypes(t *testing.T) {
 		})
 	}
 }
+
+func TestDateTypeParams(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	createGraph()
+
+	params := []interface{}{
+		domain.Date{Year: 2024, Month: time.February, Day: 29},
+		domain.LocalTime{Hour: 8, Minute: 30},
+		domain.LocalDateTime{Date: domain.Date{Year: 2024, Month: time.March, Day: 1}, Time: domain.LocalTime{Hour: 23, Minute: 59, Second: 59}},
+		domain.Duration{Days: 2, Seconds: 30},
+		// the server replies with whole seconds, so only the literal's
+		// nanoseconds key is checked here
+		domain.Duration{Seconds: 90, Nanos: 1000},
+	}
+	for _, p := range params {
+		res, err := graphInstance.Query("RETURN $v", map[string]interface{}{"v": p}, nil)
+		require.NoError(t, err)
+		require.True(t, res.Next())
+		got := res.Record().GetByIndex(0)
+		if d, ok := p.(domain.Duration); ok {
+			gotDuration, ok := got.(domain.Duration)
+			require.True(t, ok, "got %T", got)
+			want, _ := d.TimeDuration()
+			have, _ := gotDuration.TimeDuration()
+			assert.Equal(t, want.Truncate(time.Second), have)
+			continue
+		}
+		assert.Equal(t, p, got)
+	}
+}
@@ -0,0 +1,46 @@
+package integration_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/snowmerak/falkordb-go/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientDefaults(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+	createGraph()
+	defer db.SetDefaults(graph.Defaults{})
+
+	var queries []string
+	db.SetDefaults(graph.Defaults{
+		Options: graph.NewQueryOptions().SetTimeout(1000),
+		Hooks: []graph.QueryHook{func(next graph.QueryFunc) graph.QueryFunc {
+			return func(ctx context.Context, g *graph.Graph, req graph.QueryRequest) (*graph.QueryResult, error) {
+				queries = append(queries, g.Id+": "+req.Query)
+				return next(ctx, g, req)
+			}
+		}},
+	})
+
+	g := db.SelectGraph("social")
+	res, err := g.ROQuery("MATCH (p:Person) RETURN p.name", nil, nil)
+	require.NoError(t, err)
+	require.True(t, res.Next())
+	assert.Equal(t, "John Doe", res.Record().GetByIndex(0))
+	assert.Equal(t, []string{"social: MATCH (p:Person) RETURN p.name"}, queries)
+
+	_, err = g.ROQuery("UNWIND range(0, 100000000) AS x RETURN count(x)", nil, graph.NewQueryOptions().SetTimeout(1))
+	assert.Error(t, err)
+
+	ro := db.SelectGraphWithDefaults("social", graph.Defaults{ReadOnly: true})
+	_, err = ro.Query("CREATE (:Person {name: 'Nope'})", nil, nil)
+	assert.ErrorIs(t, err, graph.ErrReadOnly)
+	_, err = ro.ROQuery("MATCH (p:Person) RETURN count(p)", nil, nil)
+	assert.NoError(t, err)
+	assert.Len(t, queries, 3)
+}
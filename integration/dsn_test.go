@@ -0,0 +1,34 @@
+package integration_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	falkordb "github.com/snowmerak/falkordb-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+	addr := os.Getenv("FALKORDB_ADDR")
+	if addr == "" {
+		addr = "0.0.0.0:6379"
+	}
+	dsn := "falkor://" + addr + "?query_timeout=1000&client_name=dsn-test"
+	if os.Getenv("FALKORDB_TEST_MODE") == "cluster" {
+		dsn = "falkor+cluster://" + addr + "?query_timeout=1000"
+	}
+
+	odb, err := falkordb.Open(dsn)
+	require.NoError(t, err)
+	defer odb.Conn.Close()
+
+	res, err := odb.SelectGraph("social").ROQueryContext(context.Background(), "MATCH (p:Person) RETURN p.name", nil, nil)
+	require.NoError(t, err)
+	require.True(t, res.Next())
+	assert.Equal(t, "John Doe", res.Record().GetByIndex(0))
+}
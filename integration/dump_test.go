@@ -0,0 +1,87 @@
+package integration_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	falkordb "github.com/snowmerak/falkordb-go"
+	"github.com/snowmerak/falkordb-go/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpRestoreCypher(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	createGraph()
+	ctx := context.Background()
+
+	_, err := graphInstance.Query("CREATE INDEX FOR (p:Person) ON (p.name)", nil, nil)
+	require.NoError(t, err)
+	_, err = graphInstance.Query("CREATE (:Person {name: 'Jane', tags: ['a', 'b'], loc: point({latitude: 1.5, longitude: 2})})", nil, nil)
+	require.NoError(t, err)
+
+	var dump bytes.Buffer
+	require.NoError(t, graphInstance.DumpCypher(ctx, &dump, graph.WithDumpBatchSize(1)))
+
+	var again bytes.Buffer
+	require.NoError(t, graphInstance.DumpCypher(ctx, &again, graph.WithDumpBatchSize(1)))
+	assert.Equal(t, dump.String(), again.String())
+
+	restored := db.SelectGraph("social_restored")
+	restored.Delete()
+	defer restored.Delete()
+
+	stats, err := falkordb.RestoreCypher(ctx, restored, &dump)
+	require.NoError(t, err)
+	assert.Equal(t, 3, stats.NodesCreated)
+	assert.Equal(t, 1, stats.RelationshipsCreated)
+
+	res, err := restored.ROQuery("MATCH (p:Person)-[v:Visited]->(c:Country) RETURN p.name, v.year, c.name", nil, nil)
+	require.NoError(t, err)
+	require.True(t, res.Next())
+	assert.Equal(t, []interface{}{"John Doe", int64(2017), "Japan"}, res.Record().Values())
+
+	res, err = restored.ROQuery("MATCH (n) WHERE n:__Import OR exists(n.__import_id) RETURN count(n)", nil, nil)
+	require.NoError(t, err)
+	require.True(t, res.Next())
+	assert.Equal(t, int64(0), res.Record().GetByIndex(0))
+
+	res, err = restored.ROQuery("MATCH (p:Person {name: 'Jane'}) RETURN p.tags, p.loc", nil, nil)
+	require.NoError(t, err)
+	require.True(t, res.Next())
+	assert.Equal(t, []interface{}{"a", "b"}, res.Record().GetByIndex(0))
+}
+
+func TestDumpRestoreFloatAndControlCharacters(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	createGraph()
+	ctx := context.Background()
+
+	text := "tab\tbell\a vt\v nul\x00 \"quoted\" 'single' back\\slash"
+	_, err := graphInstance.Query("CREATE (:Value {f: $f, big: $big, s: $s})", map[string]interface{}{
+		"f": 2.0, "big": 1e21, "s": text,
+	}, nil)
+	require.NoError(t, err)
+
+	var dump bytes.Buffer
+	require.NoError(t, graphInstance.DumpCypher(ctx, &dump))
+
+	restored := db.SelectGraph("social_restored_values")
+	restored.Delete()
+	defer restored.Delete()
+
+	_, err = falkordb.RestoreCypher(ctx, restored, &dump)
+	require.NoError(t, err)
+
+	res, err := restored.ROQuery("MATCH (v:Value) RETURN v.f, v.big, v.s, typeOf(v.f)", nil, nil)
+	require.NoError(t, err)
+	require.True(t, res.Next())
+	assert.Equal(t, []interface{}{2.0, 1e21, text, "Float"}, res.Record().Values())
+}
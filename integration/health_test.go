@@ -0,0 +1,43 @@
+package integration_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPingAndHealth(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+	createGraph()
+	ctx := context.Background()
+
+	require.NoError(t, db.Ping(ctx))
+
+	h, err := db.Health(ctx)
+	require.NoError(t, err)
+	assert.Greater(t, h.ModuleVersion, 0)
+	assert.Greater(t, h.QueryLatency, time.Duration(0))
+
+	if os.Getenv("FALKORDB_TEST_MODE") == "cluster" {
+		assert.Equal(t, "ok", h.ClusterState)
+		require.NotEmpty(t, h.Nodes)
+		for _, n := range h.Nodes {
+			assert.True(t, n.Reachable, n.Addr)
+			assert.NoError(t, n.Err, n.Addr)
+			assert.Equal(t, h.ModuleVersion, n.ModuleVersion, n.Addr)
+		}
+	} else {
+		assert.Equal(t, "master", h.Role)
+		assert.Empty(t, h.Nodes)
+	}
+
+	graphs, err := db.ListGraphs()
+	require.NoError(t, err)
+	assert.NotContains(t, graphs, "__falkordb_health__")
+}
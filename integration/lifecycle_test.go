@@ -0,0 +1,63 @@
+package integration_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	falkordb "github.com/snowmerak/falkordb-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShutdown(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+	addr := os.Getenv("FALKORDB_ADDR")
+	if addr == "" {
+		addr = "0.0.0.0:6379"
+	}
+	var client *falkordb.FalkorDB
+	var err error
+	if os.Getenv("FALKORDB_TEST_MODE") == "cluster" {
+		client, err = falkordb.NewCluster(&falkordb.ConnectionClusterOption{Addrs: []string{addr}})
+	} else {
+		client, err = falkordb.FromURL("falkor://" + addr)
+	}
+	require.NoError(t, err)
+	g := client.SelectGraph("lifecycle")
+	_, err = g.Query("CREATE (:Item)", nil, nil)
+	require.NoError(t, err)
+	defer db.SelectGraph("lifecycle").Delete()
+
+	stats := client.Stats()
+	require.NotNil(t, stats.Pool)
+	assert.Equal(t, 0, stats.InFlight)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := g.ROQuery("UNWIND range(1, 3000000) AS x RETURN count(x)", nil, nil)
+		done <- err
+	}()
+	require.Eventually(t, func() bool {
+		return client.Stats().InFlight == 1
+	}, 5*time.Second, time.Millisecond)
+	assert.Equal(t, 1, client.Stats().InFlightByGraph["lifecycle"])
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	require.NoError(t, client.Shutdown(ctx))
+
+	// the query in flight completed before the pool was closed
+	require.NoError(t, <-done)
+	assert.Equal(t, 0, client.Stats().InFlight)
+
+	_, err = g.ROQuery("MATCH (n) RETURN n", nil, nil)
+	assert.True(t, errors.Is(err, falkordb.ErrClosed))
+	_, err = client.ListGraphs()
+	assert.True(t, errors.Is(err, falkordb.ErrClosed))
+	assert.True(t, errors.Is(client.Close(), falkordb.ErrClosed))
+}
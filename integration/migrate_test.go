@@ -0,0 +1,58 @@
+package integration_test
+
+import (
+	"context"
+	"testing"
+
+	falkordb "github.com/snowmerak/falkordb-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateGraph(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	createGraph()
+	ctx := context.Background()
+
+	target := db.SelectGraph("social_migrated")
+	target.Delete()
+	defer target.Delete()
+
+	for _, transport := range []falkordb.MigrateTransport{falkordb.TransportAuto, falkordb.TransportCypher, falkordb.TransportDump} {
+		t.Run(transport.String(), func(t *testing.T) {
+			res, err := falkordb.MigrateGraph(ctx, db, "social", db, "social_migrated", &falkordb.MigrateOptions{
+				Transport: transport,
+				Replace:   true,
+			})
+			require.NoError(t, err)
+			assert.NotEqual(t, falkordb.TransportAuto, res.Transport)
+			assert.Equal(t, int64(2), res.Counts.Nodes)
+			assert.Equal(t, int64(1), res.Counts.Edges)
+			assert.Equal(t, map[string]int64{"Person": 1, "Country": 1}, res.Counts.Labels)
+			assert.Equal(t, map[string]int64{"Visited": 1}, res.Counts.Relations)
+		})
+	}
+
+	_, err := falkordb.MigrateGraph(ctx, db, "social", db, "social_migrated", &falkordb.MigrateOptions{Transport: falkordb.TransportCypher})
+	assert.ErrorContains(t, err, "already exists")
+
+	// a failed copy leaves the existing target untouched
+	_, err = falkordb.MigrateGraph(ctx, db, "social_missing", db, "social_migrated", &falkordb.MigrateOptions{
+		Transport: falkordb.TransportCypher,
+		Replace:   true,
+	})
+	require.Error(t, err)
+	res, err := target.ROQuery("MATCH (n) RETURN count(n)", nil, nil)
+	require.NoError(t, err)
+	require.True(t, res.Next())
+	assert.Equal(t, int64(2), res.Record().GetByIndex(0))
+
+	graphs, err := db.ListGraphs()
+	require.NoError(t, err)
+	for _, name := range graphs {
+		assert.NotContains(t, name, ".migrating.")
+	}
+}
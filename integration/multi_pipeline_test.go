@@ -0,0 +1,60 @@
+package integration_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	falkordb "github.com/snowmerak/falkordb-go"
+	"github.com/snowmerak/falkordb-go/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiGraphPipeline(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+	createGraph()
+	ctx := context.Background()
+
+	names := make([]string, 8)
+	for i := range names {
+		names[i] = fmt.Sprintf("pipeline_tenant_%d", i)
+		g := db.SelectGraph(names[i])
+		g.Delete()
+		_, err := g.Query("CREATE (:Tenant {id: $id})", map[string]interface{}{"id": i}, nil)
+		require.NoError(t, err)
+	}
+	defer func() {
+		for _, name := range names {
+			db.SelectGraph(name).Delete()
+		}
+	}()
+
+	var reqs []falkordb.GraphRequest
+	for _, name := range names {
+		reqs = append(reqs,
+			falkordb.GraphRequest{Graph: name, Command: graph.CmdROQuery, Query: "MATCH (t:Tenant) RETURN t.id"},
+			falkordb.GraphRequest{Graph: "social", Command: graph.CmdROQuery, Query: "MATCH (p:Person) RETURN count(p)"},
+		)
+	}
+	reqs = append(reqs, falkordb.GraphRequest{Graph: names[0], Command: graph.CmdROQuery, Query: "RETURN 1 +"})
+
+	res, err := db.Pipeline(ctx, reqs)
+	require.Error(t, err)
+	require.Len(t, res.Results, len(reqs))
+	for i := range names {
+		r := res.Results[2*i]
+		require.NoError(t, res.Errors[2*i])
+		require.True(t, r.Next())
+		assert.Equal(t, int64(i), r.Record().GetByIndex(0))
+
+		r = res.Results[2*i+1]
+		require.NoError(t, res.Errors[2*i+1])
+		require.True(t, r.Next())
+		assert.Equal(t, int64(1), r.Record().GetByIndex(0))
+	}
+	assert.Error(t, res.Errors[len(reqs)-1])
+	assert.Nil(t, res.Results[len(reqs)-1])
+}
@@ -0,0 +1,69 @@
+package integration_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/snowmerak/falkordb-go/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	createGraph()
+	defer createGraph()
+
+	ctx := context.Background()
+	_, err := graphInstance.Query("UNWIND range(1, 25) AS i CREATE (:Item {seq: i})", nil, nil)
+	require.NoError(t, err)
+
+	query := "MATCH (n:Item) WHERE n.seq > $min RETURN n.seq AS seq"
+	params := map[string]interface{}{"min": 0}
+	spec := graph.PageSpec{OrderBy: "n.seq", PageSize: 10}
+
+	c, err := graphInstance.Paginate(ctx, query, params, spec)
+	require.NoError(t, err)
+
+	page, err := c.Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"seq"}, page.Results()[0].Keys())
+	assert.Len(t, page.Results(), 10)
+	assert.False(t, c.Done())
+
+	// resume from the token with a new cursor
+	spec.Token = c.Token()
+	c, err = graphInstance.Paginate(ctx, query, params, spec)
+	require.NoError(t, err)
+
+	var seqs []int64
+	for !c.Done() {
+		page, err := c.Next(ctx)
+		require.NoError(t, err)
+		for page.Next() {
+			seqs = append(seqs, page.Record().GetByIndex(0).(int64))
+		}
+	}
+	require.Len(t, seqs, 15)
+	assert.Equal(t, int64(11), seqs[0])
+	assert.Equal(t, int64(25), seqs[14])
+
+	_, err = c.Next(ctx)
+	assert.True(t, errors.Is(err, graph.ErrNoMorePages))
+
+	// a last page that is exactly full is not followed by an empty one
+	c, err = graphInstance.Paginate(ctx, query, params, graph.PageSpec{OrderBy: "n.seq", PageSize: 5})
+	require.NoError(t, err)
+	pages := 0
+	for !c.Done() {
+		page, err := c.Next(ctx)
+		require.NoError(t, err)
+		assert.Len(t, page.Results(), 5)
+		pages++
+	}
+	assert.Equal(t, 5, pages)
+}
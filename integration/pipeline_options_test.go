@@ -0,0 +1,62 @@
+package integration_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/snowmerak/falkordb-go/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPipelineWithOptionsPartialFailure(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+	createGraph()
+	ctx := context.Background()
+
+	reqs := []graph.QueryRequest{
+		{Command: graph.CmdROQuery, Query: "MATCH (p:Person) RETURN count(p)"},
+		{Command: graph.CmdROQuery, Query: "RETURN 1 +"},
+		{Command: graph.CmdROQuery, Query: "MATCH (c:Country) RETURN count(c)"},
+	}
+
+	res, err := graphInstance.PipelineWithOptions(ctx, reqs, &graph.PipelineOptions{BatchSize: 1})
+	require.Error(t, err)
+	require.NotNil(t, res)
+	assert.NoError(t, res.Errors[0])
+	assert.NotNil(t, res.Results[0])
+	assert.Error(t, res.Errors[1])
+	assert.Nil(t, res.Results[1])
+	assert.True(t, errors.Is(res.Errors[2], graph.ErrNotSent))
+
+	res, err = graphInstance.PipelineWithOptions(ctx, reqs, &graph.PipelineOptions{BatchSize: 1, ContinueOnError: true})
+	require.Error(t, err)
+	assert.NoError(t, res.Errors[2])
+	require.NotNil(t, res.Results[2])
+	assert.True(t, res.Results[2].Next())
+	assert.Equal(t, int64(1), res.Results[2].Record().GetByIndex(0))
+}
+
+func TestPipelineWithOptionsConcurrentBatches(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+	createGraph()
+	ctx := context.Background()
+
+	reqs := make([]graph.QueryRequest, 50)
+	for i := range reqs {
+		reqs[i] = graph.QueryRequest{Command: graph.CmdROQuery, Query: fmt.Sprintf("RETURN %d", i)}
+	}
+
+	res, err := graphInstance.PipelineWithOptions(ctx, reqs, &graph.PipelineOptions{BatchSize: 7, Concurrency: 3})
+	require.NoError(t, err)
+	for i, r := range res.Results {
+		require.True(t, r.Next())
+		assert.Equal(t, int64(i), r.Record().GetByIndex(0))
+	}
+}
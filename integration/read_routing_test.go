@@ -0,0 +1,38 @@
+package integration_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	falkordb "github.com/snowmerak/falkordb-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadRouting(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+	createGraph()
+
+	err := db.SetReadRouting(&falkordb.ReadRoutingOptions{Routing: falkordb.ReadReplica})
+	if os.Getenv("FALKORDB_TEST_MODE") != "cluster" {
+		assert.Error(t, err)
+		return
+	}
+	require.NoError(t, err)
+	defer db.SetReadRouting(&falkordb.ReadRoutingOptions{Routing: falkordb.ReadMaster})
+
+	g := db.SelectGraph("social")
+	for _, routing := range []falkordb.ReadRouting{falkordb.ReadReplica, falkordb.ReadNearest, falkordb.ReadRandom} {
+		require.NoError(t, db.SetReadRouting(&falkordb.ReadRoutingOptions{Routing: routing}))
+		res, err := g.ROQueryContext(context.Background(), "MATCH (p:Person) RETURN p.name", nil, nil)
+		require.NoError(t, err)
+		require.True(t, res.Next())
+		assert.Equal(t, "John Doe", res.Record().GetByIndex(0))
+	}
+
+	_, err = g.Query("CREATE (:Person {name: 'Routed'})", nil, nil)
+	assert.NoError(t, err)
+}
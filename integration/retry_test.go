@@ -0,0 +1,36 @@
+package integration_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/snowmerak/falkordb-go/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+	createGraph()
+
+	g := db.SelectGraphWithDefaults("social", graph.Defaults{Retry: &graph.RetryPolicy{
+		MaxRetries: 3,
+		Backoff:    time.Millisecond,
+		Jitter:     0.5,
+	}})
+
+	res, err := g.ROQuery("MATCH (p:Person) RETURN p.name", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, res.Retries())
+
+	res, err = g.Query("MERGE (p:Person {name: 'John Doe'}) RETURN p.age", nil, graph.NewQueryOptions().SetIdempotent(true))
+	require.NoError(t, err)
+	assert.Equal(t, 0, res.Retries())
+
+	_, err = g.ROQuery("RETURN 1 +", nil, nil)
+	assert.Error(t, err)
+	var rerr *graph.RetryError
+	assert.NotErrorAs(t, err, &rerr)
+}
@@ -0,0 +1,68 @@
+package integration_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	falkordb "github.com/snowmerak/falkordb-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSentinelURL(t *testing.T) {
+	opts, err := falkordb.ParseSentinelURL("falkor+sentinel://user:secret@h1,h2:26380,h3/mymaster/2?dial_timeout=3&pool_size=7")
+	require.NoError(t, err)
+	assert.Equal(t, "mymaster", opts.MasterName)
+	assert.Equal(t, []string{"h1:26379", "h2:26380", "h3:26379"}, opts.SentinelAddrs)
+	assert.Equal(t, 2, opts.DB)
+	assert.Equal(t, "user", opts.Username)
+	assert.Equal(t, "secret", opts.Password)
+	assert.Equal(t, "user", opts.SentinelUsername)
+	assert.Equal(t, "secret", opts.SentinelPassword)
+	assert.Equal(t, 3*time.Second, opts.DialTimeout)
+	assert.Equal(t, 7, opts.PoolSize)
+	assert.Nil(t, opts.TLSConfig)
+
+	opts, err = falkordb.ParseSentinelURL("falkors+sentinel://h1/mymaster")
+	require.NoError(t, err)
+	assert.NotNil(t, opts.TLSConfig)
+	assert.Equal(t, 0, opts.DB)
+
+	for _, bad := range []string{
+		"falkor://h1/mymaster",
+		"falkor+sentinel://h1",
+		"falkor+sentinel:///mymaster",
+		"falkor+sentinel://h1/mymaster?unknown_option=1",
+	} {
+		_, err := falkordb.ParseSentinelURL(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestSentinel(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+	url := os.Getenv("FALKORDB_SENTINEL_URL")
+	if url == "" {
+		t.Skip("FALKORDB_SENTINEL_URL not set")
+	}
+
+	sdb, err := falkordb.FromURL(url)
+	require.NoError(t, err)
+	defer sdb.Conn.Close()
+
+	events := make(chan falkordb.FailoverEvent, 1)
+	require.NoError(t, sdb.OnFailover(func(ev falkordb.FailoverEvent) { events <- ev }))
+
+	g := sdb.SelectGraph("sentinel_test")
+	defer g.Delete()
+	_, err = g.Query("CREATE (:Node {name: 'sentinel'})", nil, nil)
+	require.NoError(t, err)
+	res, err := g.ROQuery("MATCH (n:Node) RETURN n.name", nil, nil)
+	require.NoError(t, err)
+	require.True(t, res.Next())
+	assert.True(t, strings.HasPrefix(res.Record().GetByIndex(0).(string), "sentinel"))
+}
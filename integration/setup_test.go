@@ -45,7 +45,7 @@ func setup() {
 }
 
 func shutdown() {
-	graphInstance.Conn.Close()
+	db.Close()
 }
 
 func TestMain(m *testing.M) {
@@ -0,0 +1,44 @@
+package integration_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/snowmerak/falkordb-go/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	createGraph()
+	ctx := context.Background()
+
+	blob, err := graphInstance.Snapshot(ctx)
+	require.NoError(t, err)
+
+	info, _, err := graph.ReadSnapshot(bytes.NewReader(blob))
+	require.NoError(t, err)
+	assert.Equal(t, "social", info.Graph)
+	assert.Greater(t, info.ModuleVersion, 0)
+
+	restored := db.SelectGraph("social_snapshot")
+	restored.Delete()
+	defer restored.Delete()
+
+	require.NoError(t, db.RestoreGraph(ctx, "social_snapshot", blob, false))
+	assert.Error(t, db.RestoreGraph(ctx, "social_snapshot", blob, false))
+
+	var buf bytes.Buffer
+	require.NoError(t, graphInstance.SnapshotTo(ctx, &buf))
+	require.NoError(t, db.RestoreGraphFrom(ctx, "social_snapshot", &buf, true))
+
+	res, err := restored.ROQuery("MATCH (p:Person)-[:Visited]->(c:Country) RETURN p.name, c.name", nil, nil)
+	require.NoError(t, err)
+	require.True(t, res.Next())
+	assert.Equal(t, []interface{}{"John Doe", "Japan"}, res.Record().Values())
+}
@@ -0,0 +1,40 @@
+package integration_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryStream(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	createGraph()
+
+	s, err := graphInstance.ROQueryStream(context.Background(), "UNWIND range(1, 10000) AS x RETURN x", nil, nil)
+	require.NoError(t, err)
+	defer s.Close()
+
+	assert.Equal(t, []string{"x"}, s.Columns())
+	count := 0
+	for s.Next() {
+		count++
+		assert.Equal(t, int64(count), s.Record().GetByIndex(0))
+	}
+	assert.NoError(t, s.Err())
+	assert.Equal(t, 10000, count)
+	assert.Greater(t, s.Stats().InternalExecutionTime, time.Duration(0))
+
+	s, err = graphInstance.QueryStream(context.Background(), "MATCH (p:Person) RETURN p.name", nil, nil)
+	require.NoError(t, err)
+	defer s.Close()
+	require.True(t, s.Next())
+	assert.Equal(t, "John Doe", s.Record().GetByIndex(0))
+	assert.False(t, s.Next())
+	assert.NoError(t, s.Err())
+}
@@ -0,0 +1,73 @@
+package integration_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/snowmerak/falkordb-go/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTx(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	createGraph()
+	defer createGraph()
+	ctx := context.Background()
+
+	res, err := graphInstance.Tx(ctx, func(tx *graph.GraphTx) error {
+		tx.Query("CREATE (:Person {name: 'Tx'})", nil, nil)
+		tx.Query("MATCH (p:Person {name: 'Tx'}) SET p.age = 1", nil, nil)
+		tx.ROQuery("MATCH (p:Person) RETURN count(p)", nil, nil)
+		return nil
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.Attempts)
+	assert.Equal(t, 1, res.Results[0].Stats().NodesCreated)
+	assert.Equal(t, 1, res.Results[1].Stats().PropertiesSet)
+	require.True(t, res.Results[2].Next())
+	assert.Equal(t, int64(2), res.Results[2].Record().GetByIndex(0))
+
+	// a failing statement does not hide the others
+	res, err = graphInstance.Tx(ctx, func(tx *graph.GraphTx) error {
+		tx.Query("CREATE (:Person {name: 'Ok'})", nil, nil)
+		tx.Query("RETURN nosuchfunction(1)", nil, nil)
+		return nil
+	}, nil)
+	assert.ErrorContains(t, err, "statement 1")
+	require.NotNil(t, res)
+	assert.NoError(t, res.Errors[0])
+	assert.Error(t, res.Errors[1])
+
+	// a concurrent write between read and EXEC triggers a retry
+	calls := 0
+	res, err = graphInstance.Tx(ctx, func(tx *graph.GraphTx) error {
+		calls++
+		r, err := tx.Read(ctx, "MATCH (p:Person {name: 'Tx'}) RETURN p.age", nil)
+		if err != nil {
+			return err
+		}
+		r.Next()
+		age := r.Record().GetByIndex(0).(int64)
+		if calls == 1 {
+			_, err := graphInstance.Query("MATCH (p:Person {name: 'Tx'}) SET p.age = 10", nil, nil)
+			require.NoError(t, err)
+		}
+		tx.Query("MATCH (p:Person {name: 'Tx'}) SET p.age = $age", map[string]interface{}{"age": age + 1}, nil)
+		return nil
+	}, &graph.TxOptions{Watch: true})
+	require.NoError(t, err)
+	assert.Equal(t, 2, res.Attempts)
+
+	r, err := graphInstance.ROQuery("MATCH (p:Person {name: 'Tx'}) RETURN p.age", nil, nil)
+	require.NoError(t, err)
+	require.True(t, r.Next())
+	assert.Equal(t, int64(11), r.Record().GetByIndex(0))
+
+	ro := db.SelectGraph("social")
+	_, err = graph.NewWithMode("social", ro.Conn, true).Tx(ctx, func(tx *graph.GraphTx) error { return nil }, nil)
+	assert.ErrorIs(t, err, graph.ErrReadOnly)
+}
@@ -0,0 +1,163 @@
+package falkordb
+
+import (
+	"context"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrClosed is returned by queries after Close or Shutdown. It is the
+// go-redis error, so that commands reaching the closed pool match it too.
+var ErrClosed = redis.ErrClosed
+
+// Stats holds statistics of a client.
+type Stats struct {
+	// Pool holds the connection pool statistics of go-redis.
+	Pool *redis.PoolStats
+	// InFlight is the number of queries, pipelines, transactions, bulk
+	// writes, snapshots and open streams running.
+	InFlight int
+	// InFlightByGraph is InFlight per graph.
+	InFlightByGraph map[string]int
+}
+
+// lifecycle tracks the work in flight and stops admitting new work once the
+// client shuts down. It is the graph.Tracker of every selected graph.
+type lifecycle struct {
+	mu       sync.Mutex
+	closed   bool
+	inFlight map[string]int
+	total    int
+	idle     chan struct{}
+	abort    context.Context
+	cancel   context.CancelFunc
+}
+
+// Enter admits work on graphName unless the client is closed. The returned
+// context is cancelled once Shutdown stops waiting.
+func (l *lifecycle) Enter(graphName string) (context.Context, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil, ErrClosed
+	}
+	if l.inFlight == nil {
+		l.inFlight = make(map[string]int)
+	}
+	if l.abort == nil {
+		l.abort, l.cancel = context.WithCancel(context.Background())
+	}
+	l.inFlight[graphName]++
+	l.total++
+	return l.abort, nil
+}
+
+// Leave reports that work admitted by Enter is done.
+func (l *lifecycle) Leave(graphName string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.inFlight[graphName]--; l.inFlight[graphName] == 0 {
+		delete(l.inFlight, graphName)
+	}
+	if l.total--; l.total == 0 && l.idle != nil {
+		close(l.idle)
+		l.idle = nil
+	}
+}
+
+// close stops admitting queries. It returns a channel closed once no query
+// is in flight, or false if the client was already closed.
+func (l *lifecycle) close() (<-chan struct{}, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil, false
+	}
+	l.closed = true
+	idle := make(chan struct{})
+	if l.total == 0 {
+		close(idle)
+	} else {
+		l.idle = idle
+	}
+	return idle, true
+}
+
+// stop aborts the work still in flight, such as open streams.
+func (l *lifecycle) stop() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.cancel != nil {
+		l.cancel()
+	}
+}
+
+func (l *lifecycle) isClosed() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.closed
+}
+
+func (l *lifecycle) stats() (int, map[string]int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	byGraph := make(map[string]int, len(l.inFlight))
+	for name, n := range l.inFlight {
+		byGraph[name] = n
+	}
+	return l.total, byGraph
+}
+
+// Close stops accepting queries, waits for the work in flight and closes
+// the connections. It returns ErrClosed if the client was already closed.
+func (db *FalkorDB) Close() error {
+	return db.Shutdown(context.Background())
+}
+
+// Shutdown stops accepting queries, which then fail with ErrClosed, and
+// waits for the work in flight until ctx is done: queries, pipelines,
+// transactions, bulk writes, snapshots and open streams, which are waited
+// for until they are closed. The connections, including those of streams,
+// are closed in either case; ctx.Err() is returned if work was still running.
+func (db *FalkorDB) Shutdown(ctx context.Context) error {
+	idle, ok := db.life.close()
+	if !ok {
+		return ErrClosed
+	}
+
+	var waitErr error
+	select {
+	case <-idle:
+	case <-ctx.Done():
+		waitErr = ctx.Err()
+	}
+	db.life.stop()
+
+	db.watcherOnce.Do(func() {})
+	if db.watcher != nil {
+		db.watcher.cancel()
+	}
+	var err error
+	if db.reads != nil {
+		err = db.reads.close()
+	}
+	if cerr := db.Conn.Close(); cerr != nil && err == nil {
+		err = cerr
+	}
+	if waitErr != nil {
+		return waitErr
+	}
+	return err
+}
+
+// Stats returns the connection pool statistics and the number of queries in
+// flight.
+func (db *FalkorDB) Stats() Stats {
+	total, byGraph := db.life.stats()
+	return Stats{
+		Pool:            db.Conn.PoolStats(),
+		InFlight:        total,
+		InFlightByGraph: byGraph,
+	}
+}
@@ -0,0 +1,128 @@
+package falkordb
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/snowmerak/falkordb-go/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingHook holds every pipeline until release is closed.
+type blockingHook struct {
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (h blockingHook) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (h blockingHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook { return next }
+
+func (h blockingHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		h.entered <- struct{}{}
+		<-h.release
+		for _, cmd := range cmds {
+			cmd.SetErr(redis.ErrClosed)
+		}
+		return nil
+	}
+}
+
+func TestShutdownWaitsForPipelinesTxAndBulkWrites(t *testing.T) {
+	runs := map[string]func(g *graph.Graph) error{
+		"pipeline": func(g *graph.Graph) error {
+			_, err := g.PipelineWithOptions(context.Background(), []graph.QueryRequest{{Query: "RETURN 1"}}, nil)
+			return err
+		},
+		"tx": func(g *graph.Graph) error {
+			_, err := g.Tx(context.Background(), func(tx *graph.GraphTx) error {
+				tx.Query("CREATE ()", nil, nil)
+				return nil
+			}, nil)
+			return err
+		},
+		"bulk": func(g *graph.Graph) error {
+			_, err := g.BulkCreateNodes(context.Background(), "L", []map[string]interface{}{{"v": 1}}, nil)
+			return err
+		},
+	}
+	for name, run := range runs {
+		t.Run(name, func(t *testing.T) {
+			hook := blockingHook{entered: make(chan struct{}), release: make(chan struct{})}
+			conn := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1"})
+			conn.AddHook(hook)
+			db := &FalkorDB{Conn: conn}
+			g := db.SelectGraph("g")
+
+			done := make(chan error)
+			go func() { done <- run(g) }()
+			<-hook.entered
+			assert.Equal(t, map[string]int{"g": 1}, db.Stats().InFlightByGraph)
+
+			shutdown := make(chan error)
+			go func() { shutdown <- db.Shutdown(context.Background()) }()
+			select {
+			case err := <-shutdown:
+				t.Fatalf("Shutdown returned %v while %s was in flight", err, name)
+			case <-time.After(20 * time.Millisecond):
+			}
+
+			close(hook.release)
+			<-done
+			require.NoError(t, <-shutdown)
+			assert.ErrorIs(t, run(g), ErrClosed)
+		})
+	}
+}
+
+func TestShutdownClosesStreams(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	var accepted atomic.Int32
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			accepted.Add(1)
+			go func() {
+				defer c.Close()
+				buf := make([]byte, 512)
+				if _, err := c.Read(buf); err != nil {
+					return
+				}
+				// the header, two records announced, only the first sent
+				_, _ = c.Write([]byte("*3\r\n*1\r\n*2\r\n:1\r\n$1\r\nv\r\n*2\r\n*1\r\n*2\r\n:3\r\n:1\r\n"))
+				_, _ = c.Read(buf)
+			}()
+		}
+	}()
+
+	db := &FalkorDB{Conn: redis.NewClient(&redis.Options{Addr: ln.Addr().String()})}
+	g := db.SelectGraph("g")
+	s, err := g.ROQueryStream(context.Background(), "UNWIND [1, 2] AS v RETURN v", nil, nil)
+	require.NoError(t, err)
+	require.True(t, s.Next())
+	assert.Equal(t, 1, db.Stats().InFlight)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, db.Shutdown(ctx), context.DeadlineExceeded)
+
+	assert.False(t, s.Next())
+	assert.ErrorIs(t, s.Err(), ErrClosed)
+	require.NoError(t, s.Close())
+	assert.Equal(t, 0, db.Stats().InFlight)
+
+	_, err = g.ROQueryStream(context.Background(), "RETURN 1", nil, nil)
+	assert.ErrorIs(t, err, ErrClosed)
+	assert.Equal(t, int32(1), accepted.Load(), "no connection is dialed after Shutdown")
+}
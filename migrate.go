@@ -0,0 +1,337 @@
+package falkordb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/snowmerak/falkordb-go/graph"
+	"github.com/snowmerak/falkordb-go/util/hashslot"
+	"github.com/snowmerak/falkordb-go/util/strs"
+)
+
+// MigrateTransport selects how MigrateGraph copies a graph.
+type MigrateTransport int
+
+const (
+	// TransportAuto uses DUMP/RESTORE when both servers run the same module
+	// version and Cypher streaming otherwise, or when the destination cannot
+	// restore the dump, for example because it uses a different RDB version.
+	TransportAuto MigrateTransport = iota
+	// TransportDump copies the graph key with DUMP/RESTORE.
+	TransportDump
+	// TransportCypher streams the graph as a Cypher script.
+	TransportCypher
+)
+
+func (t MigrateTransport) String() string {
+	switch t {
+	case TransportAuto:
+		return "auto"
+	case TransportDump:
+		return "dump"
+	case TransportCypher:
+		return "cypher"
+	default:
+		return fmt.Sprintf("MigrateTransport(%d)", int(t))
+	}
+}
+
+// MigrateOptions configures MigrateGraph.
+type MigrateOptions struct {
+	Transport MigrateTransport
+	// Replace overwrites an existing destination graph. The existing graph
+	// is only replaced once the copy is complete and verified.
+	Replace bool
+	// CopyUDFs loads the source's UDF libraries on the destination,
+	// replacing libraries of the same name.
+	CopyUDFs bool
+	// SkipVerify skips comparing the graphs after the copy.
+	SkipVerify bool
+	// BatchSize is the number of nodes or edges per statement when
+	// streaming Cypher.
+	BatchSize int
+}
+
+// GraphCounts summarises a graph for verification.
+type GraphCounts struct {
+	Nodes     int64
+	Edges     int64
+	Labels    map[string]int64
+	Relations map[string]int64
+}
+
+// MigrateResult describes a completed migration.
+type MigrateResult struct {
+	// Transport is the transport that was used.
+	Transport MigrateTransport
+	// Counts are the counts of the destination graph; they are empty when
+	// verification was skipped.
+	Counts GraphCounts
+}
+
+// VerificationError reports a destination graph that differs from its source.
+type VerificationError struct {
+	Source      GraphCounts
+	Destination GraphCounts
+}
+
+func (e *VerificationError) Error() string {
+	var diffs []string
+	if e.Source.Nodes != e.Destination.Nodes {
+		diffs = append(diffs, fmt.Sprintf("nodes %d != %d", e.Source.Nodes, e.Destination.Nodes))
+	}
+	if e.Source.Edges != e.Destination.Edges {
+		diffs = append(diffs, fmt.Sprintf("edges %d != %d", e.Source.Edges, e.Destination.Edges))
+	}
+	diffs = append(diffs, histogramDiff("label", e.Source.Labels, e.Destination.Labels)...)
+	diffs = append(diffs, histogramDiff("relationship type", e.Source.Relations, e.Destination.Relations)...)
+	return "migrated graph differs from source: " + strings.Join(diffs, ", ")
+}
+
+func histogramDiff(kind string, src, dst map[string]int64) []string {
+	names := make(map[string]struct{})
+	for k := range src {
+		names[k] = struct{}{}
+	}
+	for k := range dst {
+		names[k] = struct{}{}
+	}
+	sorted := make([]string, 0, len(names))
+	for k := range names {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var diffs []string
+	for _, k := range sorted {
+		if src[k] != dst[k] {
+			diffs = append(diffs, fmt.Sprintf("%s %s %d != %d", kind, k, src[k], dst[k]))
+		}
+	}
+	return diffs
+}
+
+// MigrateGraph copies the graph srcName of src to dstName on dst, which may
+// be a different server or cluster. Indexes and constraints are copied with
+// the graph by both transports. After the copy the node and edge counts and
+// the label and relationship type histograms of both graphs are compared,
+// and a difference is reported as a *VerificationError. Both transports copy
+// into a staging graph that only replaces the destination once it is
+// verified.
+func MigrateGraph(ctx context.Context, src *FalkorDB, srcName string, dst *FalkorDB, dstName string, opts *MigrateOptions) (*MigrateResult, error) {
+	var o MigrateOptions
+	if opts != nil {
+		o = *opts
+	}
+	if dst.readOnly() {
+		return nil, graph.ErrReadOnly
+	}
+	if src == dst && srcName == dstName {
+		return nil, errors.New("source and destination graph are the same")
+	}
+
+	source := src.SelectGraph(srcName)
+	target := dst.SelectGraph(dstName)
+
+	transport := o.Transport
+	switch transport {
+	case TransportAuto, TransportDump, TransportCypher:
+	default:
+		return nil, fmt.Errorf("unknown transport %v", transport)
+	}
+	if transport == TransportAuto {
+		srcVersion, err := source.ModuleVersion(ctx)
+		if err != nil {
+			return nil, err
+		}
+		dstVersion, err := target.ModuleVersion(ctx)
+		if err != nil {
+			return nil, err
+		}
+		transport = TransportCypher
+		if srcVersion == dstVersion {
+			transport = TransportDump
+		}
+	}
+
+	if o.CopyUDFs {
+		if err := copyUDFs(src, dst); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &MigrateResult{Transport: transport}
+	if err := migrateStaged(ctx, source, dst, target, o, result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// verifyCopy compares the counts of a copy with its source and returns the
+// counts of the copy.
+func verifyCopy(ctx context.Context, source, copied *graph.Graph) (GraphCounts, error) {
+	srcCounts, err := countGraph(ctx, source)
+	if err != nil {
+		return GraphCounts{}, err
+	}
+	dstCounts, err := countGraph(ctx, copied)
+	if err != nil {
+		return GraphCounts{}, err
+	}
+	if !reflect.DeepEqual(srcCounts, dstCounts) {
+		return dstCounts, &VerificationError{Source: srcCounts, Destination: dstCounts}
+	}
+	return dstCounts, nil
+}
+
+// migrateStaged copies the source into a staging graph, verifies it and
+// renames it over the target, so that a failed copy leaves an existing target
+// untouched. With TransportAuto a dump the destination fails to restore is
+// copied again by Cypher streaming, and result.Transport is updated.
+func migrateStaged(ctx context.Context, source *graph.Graph, dst *FalkorDB, target *graph.Graph, o MigrateOptions, result *MigrateResult) error {
+	exists, err := target.Conn.Exists(ctx, target.Id).Result()
+	if err != nil {
+		return err
+	}
+	if exists > 0 && !o.Replace {
+		return fmt.Errorf("graph %s already exists", target.Id)
+	}
+
+	key, err := stagingKey(target.Id)
+	if err != nil {
+		return err
+	}
+	staging := dst.SelectGraph(key)
+
+	if result.Transport == TransportDump {
+		blob, err := source.Snapshot(ctx)
+		if err != nil {
+			return err
+		}
+		if err := dst.RestoreGraph(ctx, staging.Id, blob, false); err != nil {
+			_ = staging.Delete()
+			if o.Transport != TransportAuto || ctx.Err() != nil {
+				return err
+			}
+			// the destination cannot load the dump, for example because of
+			// an RDB version it does not know, so stream Cypher instead
+			result.Transport = TransportCypher
+		}
+	}
+	if result.Transport == TransportCypher {
+		if err := streamCypher(ctx, source, staging, o); err != nil {
+			_ = staging.Delete()
+			return err
+		}
+	}
+	if !o.SkipVerify {
+		if result.Counts, err = verifyCopy(ctx, source, staging); err != nil {
+			_ = staging.Delete()
+			return err
+		}
+	}
+	if err := target.Conn.Rename(ctx, staging.Id, target.Id).Err(); err != nil {
+		_ = staging.Delete()
+		return err
+	}
+	return nil
+}
+
+// stagingKey returns a fresh key in the hash slot of name, so that it can be
+// renamed to name on a cluster.
+func stagingKey(name string) (string, error) {
+	suffix := ".migrating." + strs.RandomString(8)
+	for _, key := range []string{name + suffix, "{" + name + "}" + suffix} {
+		if hashslot.Slot(key) == hashslot.Slot(name) {
+			return key, nil
+		}
+	}
+	return "", fmt.Errorf("no staging key in the hash slot of graph %s", name)
+}
+
+// streamCypher pipes the source dump into target.
+func streamCypher(ctx context.Context, source, target *graph.Graph, o MigrateOptions) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pr, pw := io.Pipe()
+	dumpErr := make(chan error, 1)
+	go func() {
+		var opts []graph.DumpOption
+		if o.BatchSize > 0 {
+			opts = append(opts, graph.WithDumpBatchSize(o.BatchSize))
+		}
+		err := source.DumpCypher(ctx, pw, opts...)
+		pw.CloseWithError(err)
+		dumpErr <- err
+	}()
+
+	_, err := RestoreCypher(ctx, target, pr)
+	pr.CloseWithError(err)
+	cancel()
+	if derr := <-dumpErr; derr != nil && err == nil {
+		return derr
+	}
+	return err
+}
+
+func copyUDFs(src, dst *FalkorDB) error {
+	libs, err := src.ListUDF(WithUDFCode())
+	if err != nil {
+		return err
+	}
+	for _, lib := range libs {
+		if err := dst.LoadUDFReplace(lib.Name, lib.Code); err != nil {
+			return fmt.Errorf("loading UDF library %s: %w", lib.Name, err)
+		}
+	}
+	return nil
+}
+
+func countGraph(ctx context.Context, g *graph.Graph) (GraphCounts, error) {
+	counts := GraphCounts{Labels: map[string]int64{}, Relations: map[string]int64{}}
+
+	total := func(query string) (int64, error) {
+		qr, err := g.ROQueryContext(ctx, query, nil, nil)
+		if err != nil {
+			return 0, err
+		}
+		if !qr.Next() {
+			return 0, nil
+		}
+		n, _ := qr.Record().GetByIndex(0).(int64)
+		return n, nil
+	}
+	histogram := func(query string, into map[string]int64) error {
+		qr, err := g.ROQueryContext(ctx, query, nil, nil)
+		if err != nil {
+			return err
+		}
+		for qr.Next() {
+			name, _ := qr.Record().GetByIndex(0).(string)
+			n, _ := qr.Record().GetByIndex(1).(int64)
+			into[name] = n
+		}
+		return nil
+	}
+
+	var err error
+	if counts.Nodes, err = total("MATCH (n) RETURN count(n)"); err != nil {
+		return counts, err
+	}
+	if counts.Edges, err = total("MATCH ()-[e]->() RETURN count(e)"); err != nil {
+		return counts, err
+	}
+	if err := histogram("MATCH (n) UNWIND labels(n) AS l RETURN l, count(*)", counts.Labels); err != nil {
+		return counts, err
+	}
+	if err := histogram("MATCH ()-[e]->() RETURN type(e), count(e)", counts.Relations); err != nil {
+		return counts, err
+	}
+	return counts, nil
+}
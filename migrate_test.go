@@ -0,0 +1,102 @@
+package falkordb
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// migrateHook answers the commands of a migration between two graphs on one
+// fake server and records them. RESTORE fails with restoreErr when it is set.
+type migrateHook struct {
+	restoreErr error
+	cmds       *[][]interface{}
+}
+
+func (h migrateHook) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (h migrateHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		*h.cmds = append(*h.cmds, cmd.Args())
+		switch c := cmd.(type) {
+		case *redis.Cmd:
+			if strings.EqualFold(cmd.Name(), "module") {
+				c.SetVal([]interface{}{[]interface{}{"name", "graph", "ver", int64(41000)}})
+				break
+			}
+			c.SetErr(errors.New("ERR unexpected command"))
+		case *redis.StringCmd:
+			c.SetVal("payload")
+		case *redis.IntCmd:
+			c.SetVal(0)
+		case *redis.StatusCmd:
+			if strings.EqualFold(cmd.Name(), "restore") && h.restoreErr != nil {
+				c.SetErr(h.restoreErr)
+				break
+			}
+			c.SetVal("OK")
+		}
+		return cmd.Err()
+	}
+}
+
+func (h migrateHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func migrateDB(t *testing.T, restoreErr error) (*FalkorDB, *[][]interface{}) {
+	cmds := &[][]interface{}{}
+	conn := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1"})
+	conn.AddHook(migrateHook{restoreErr: restoreErr, cmds: cmds})
+	t.Cleanup(func() { conn.Close() })
+	return &FalkorDB{Conn: conn}, cmds
+}
+
+// commandsNamed returns the recorded commands named name.
+func commandsNamed(cmds [][]interface{}, name string) [][]interface{} {
+	var out [][]interface{}
+	for _, args := range cmds {
+		if n, ok := args[0].(string); ok && strings.EqualFold(n, name) {
+			out = append(out, args)
+		}
+	}
+	return out
+}
+
+func TestMigrateDumpRestoresIntoStaging(t *testing.T) {
+	db, cmds := migrateDB(t, nil)
+	res, err := MigrateGraph(context.Background(), db, "src", db, "dst", &MigrateOptions{Transport: TransportDump, SkipVerify: true})
+	require.NoError(t, err)
+	assert.Equal(t, TransportDump, res.Transport)
+
+	restores := commandsNamed(*cmds, "restore")
+	require.Len(t, restores, 1)
+	staging := restores[0][1].(string)
+	assert.Contains(t, staging, ".migrating.")
+	assert.Equal(t, [][]interface{}{{"rename", staging, "dst"}}, commandsNamed(*cmds, "rename"))
+}
+
+func TestMigrateDumpRestoreFailure(t *testing.T) {
+	restoreErr := errors.New("ERR DUMP payload version or checksum are wrong")
+
+	db, cmds := migrateDB(t, restoreErr)
+	res, err := MigrateGraph(context.Background(), db, "src", db, "dst", &MigrateOptions{Transport: TransportDump, SkipVerify: true})
+	assert.Equal(t, restoreErr, err)
+	assert.Equal(t, TransportDump, res.Transport)
+	assert.Empty(t, commandsNamed(*cmds, "rename"))
+	assert.Len(t, commandsNamed(*cmds, "GRAPH.DELETE"), 1)
+
+	// TransportAuto streams Cypher instead, which fails here at its first query
+	db, cmds = migrateDB(t, restoreErr)
+	res, err = MigrateGraph(context.Background(), db, "src", db, "dst", &MigrateOptions{SkipVerify: true})
+	assert.EqualError(t, err, "ERR unexpected command")
+	assert.Equal(t, TransportCypher, res.Transport)
+	assert.Len(t, commandsNamed(*cmds, "restore"), 1)
+	assert.NotEmpty(t, commandsNamed(*cmds, "GRAPH.RO_QUERY"))
+	assert.Empty(t, commandsNamed(*cmds, "rename"))
+}
@@ -0,0 +1,161 @@
+package falkordb
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/snowmerak/falkordb-go/graph"
+	"github.com/snowmerak/falkordb-go/util/hashslot"
+)
+
+// GraphRequest is a query against a named graph, sent by FalkorDB.Pipeline.
+type GraphRequest struct {
+	Graph string
+	// Command is graph.CmdQuery (the default when empty) or graph.CmdROQuery.
+	Command string
+	Query   string
+	Params  map[string]interface{}
+	Options *graph.QueryOptions
+}
+
+// pipelineGroup is a set of requests sent on one pipeline.
+type pipelineGroup struct {
+	conn interface {
+		Pipeline() redis.Pipeliner
+	}
+	reqs []int
+}
+
+// Pipeline sends requests against any number of graphs and reports a result
+// or an error for each request, in order. The returned error is the first
+// request error, as returned by PipelineResult.Err.
+//
+// On a cluster the requests are grouped by the hash slot of their graph and
+// every group is sent to the master owning the slot. Groups owned by the same
+// master share a round trip, and masters are queried in parallel. Requests
+// redirected because a slot moved are retried through the cluster client.
+func (db *FalkorDB) Pipeline(ctx context.Context, reqs []GraphRequest) (*graph.PipelineResult, error) {
+	res := &graph.PipelineResult{
+		Results: make([]*graph.QueryResult, len(reqs)),
+		Errors:  make([]error, len(reqs)),
+	}
+
+	graphs := make(map[string]*graph.Graph)
+	args := make([][]interface{}, len(reqs))
+	for i, req := range reqs {
+		g, ok := graphs[req.Graph]
+		if !ok {
+			g = db.SelectGraph(req.Graph)
+			graphs[req.Graph] = g
+		}
+		args[i], res.Errors[i] = g.PipelineArgs(graph.QueryRequest{
+			Command: req.Command,
+			Query:   req.Query,
+			Params:  req.Params,
+			Options: req.Options,
+		})
+	}
+
+	for name := range graphs {
+		if _, err := db.life.Enter(name); err != nil {
+			return nil, err
+		}
+		defer db.life.Leave(name)
+	}
+
+	groups := db.pipelineGroups(ctx, reqs, args, res.Errors)
+
+	var wg sync.WaitGroup
+	for _, group := range groups {
+		wg.Add(1)
+		go func(group *pipelineGroup) {
+			defer wg.Done()
+			db.execPipelineGroup(ctx, group, reqs, args, graphs, res)
+		}(group)
+	}
+	wg.Wait()
+
+	return res, res.Err()
+}
+
+// pipelineGroups groups the sendable requests by the connection they are sent
+// on. Requests whose slot owner cannot be resolved fail with that error.
+func (db *FalkorDB) pipelineGroups(ctx context.Context, reqs []GraphRequest, args [][]interface{}, errs []error) []*pipelineGroup {
+	cc, ok := db.Conn.(*redis.ClusterClient)
+	if !ok {
+		group := &pipelineGroup{conn: db.Conn}
+		for i, a := range args {
+			if a != nil {
+				group.reqs = append(group.reqs, i)
+			}
+		}
+		if len(group.reqs) == 0 {
+			return nil
+		}
+		return []*pipelineGroup{group}
+	}
+
+	var slots []int
+	bySlot := make(map[int][]int)
+	for i, a := range args {
+		if a == nil {
+			continue
+		}
+		slot := hashslot.Slot(reqs[i].Graph)
+		if _, ok := bySlot[slot]; !ok {
+			slots = append(slots, slot)
+		}
+		bySlot[slot] = append(bySlot[slot], i)
+	}
+
+	var groups []*pipelineGroup
+	byMaster := make(map[*redis.Client]*pipelineGroup)
+	for _, slot := range slots {
+		idx := bySlot[slot]
+		master, err := cc.MasterForKey(ctx, reqs[idx[0]].Graph)
+		if err != nil {
+			for _, i := range idx {
+				errs[i] = err
+			}
+			continue
+		}
+		group, ok := byMaster[master]
+		if !ok {
+			group = &pipelineGroup{conn: master}
+			byMaster[master] = group
+			groups = append(groups, group)
+		}
+		group.reqs = append(group.reqs, idx...)
+	}
+	return groups
+}
+
+func (db *FalkorDB) execPipelineGroup(ctx context.Context, group *pipelineGroup, reqs []GraphRequest, args [][]interface{}, graphs map[string]*graph.Graph, res *graph.PipelineResult) {
+	pipe := group.conn.Pipeline()
+	cmds := make([]*redis.Cmd, len(group.reqs))
+	for j, i := range group.reqs {
+		cmds[j] = pipe.Do(ctx, args[i]...)
+	}
+	_, _ = pipe.Exec(ctx)
+
+	for j, i := range group.reqs {
+		reply, err := cmds[j].Result()
+		if err != nil && isRedirect(err) {
+			reply, err = db.Conn.Do(ctx, args[i]...).Result()
+		}
+		if err != nil {
+			res.Errors[i] = err
+			continue
+		}
+		res.Results[i], res.Errors[i] = graph.QueryResultNew(graphs[reqs[i].Graph], reply)
+	}
+}
+
+// isRedirect reports whether err is a cluster MOVED or ASK redirection.
+func isRedirect(err error) bool {
+	msg := err.Error()
+	return strings.HasPrefix(msg, "MOVED ") || strings.HasPrefix(msg, "ASK ")
+}
@@ -0,0 +1,532 @@
+package falkordb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/snowmerak/falkordb-go/util/hashslot"
+)
+
+// ReadRouting selects where read only queries are sent.
+type ReadRouting int
+
+const (
+	// ReadMaster sends every query to the master.
+	ReadMaster ReadRouting = iota
+	// ReadReplica sends read only queries to a random healthy replica, and to
+	// the master only when no replica is available.
+	ReadReplica
+	// ReadNearest sends read only queries to the node with the lowest latency
+	// among the master and its healthy replicas.
+	ReadNearest
+	// ReadRandom spreads read only queries at random over the master and its
+	// healthy replicas.
+	ReadRandom
+)
+
+func (r ReadRouting) String() string {
+	switch r {
+	case ReadMaster:
+		return "master"
+	case ReadReplica:
+		return "replica"
+	case ReadNearest:
+		return "nearest"
+	case ReadRandom:
+		return "random"
+	default:
+		return fmt.Sprintf("ReadRouting(%d)", int(r))
+	}
+}
+
+const defaultReadRefreshInterval = time.Second
+
+// ReadRoutingOptions configures the routing of read only queries.
+type ReadRoutingOptions struct {
+	Routing ReadRouting
+	// MaxLagBytes excludes replicas whose replication offset is more than
+	// MaxLagBytes behind the offset of their master. Replicas whose link to
+	// the master is down or that are still syncing are always excluded.
+	// Zero disables the lag check.
+	MaxLagBytes int64
+	// RefreshInterval is how often the replicas and their health are
+	// rediscovered (default 1s).
+	RefreshInterval time.Duration
+}
+
+// SetReadRouting routes ROQuery and ROQueryContext of graphs selected
+// afterwards to replicas on cluster and sentinel deployments. Writes always
+// go to the master, and so does a read when no replica is healthy or the
+// chosen replica cannot serve it. Calling it again changes the routing of
+// all graphs that route reads.
+func (db *FalkorDB) SetReadRouting(opts *ReadRoutingOptions) error {
+	var o ReadRoutingOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.RefreshInterval <= 0 {
+		o.RefreshInterval = defaultReadRefreshInterval
+	}
+
+	if db.reads == nil {
+		if o.Routing == ReadMaster {
+			return nil
+		}
+		r, err := newReadRouter(db)
+		if err != nil {
+			return err
+		}
+		db.reads = r
+	}
+
+	db.reads.mu.Lock()
+	db.reads.opts = o
+	db.reads.mu.Unlock()
+	return nil
+}
+
+// clusterReadRouting maps the read flags of cluster options to a routing.
+func clusterReadRouting(options *ConnectionClusterOption) ReadRouting {
+	switch {
+	case options.RouteByLatency:
+		return ReadNearest
+	case options.RouteRandomly:
+		return ReadRandom
+	case options.ReadOnly:
+		return ReadReplica
+	default:
+		return ReadMaster
+	}
+}
+
+// replicaShard is a slot range with the addresses of its master and replicas.
+type replicaShard struct {
+	start, end int
+	master     string
+	replicas   []string
+}
+
+type routedNode struct {
+	client  *redis.Client
+	healthy bool
+	latency time.Duration
+	// offset is the replication offset, or -1 when unknown.
+	offset int64
+}
+
+// readRouter implements graph.ReadRouter on top of a discovered topology.
+type readRouter struct {
+	discover func(ctx context.Context) ([]replicaShard, error)
+	dial     func(addr string) *redis.Client
+	cluster  bool
+
+	refreshMu sync.Mutex
+	// stop is cancelled by close to abort background refreshes, which wg
+	// waits for.
+	stop   context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu         sync.Mutex
+	opts       ReadRoutingOptions
+	shards     []replicaShard
+	nodes      map[string]*routedNode
+	refreshed  time.Time
+	refreshing bool
+	closed     bool
+}
+
+func newReadRouter(db *FalkorDB) (*readRouter, error) {
+	if db.failover != nil {
+		return newTopologyRouter(sentinelTopology(db.failover)), nil
+	}
+	cc, ok := db.Conn.(*redis.ClusterClient)
+	if !ok {
+		return nil, errors.New("read routing requires a cluster or sentinel connection")
+	}
+	r := newTopologyRouter(clusterTopology(cc))
+	r.cluster = true
+	return r, nil
+}
+
+func newTopologyRouter(discover func(ctx context.Context) ([]replicaShard, error), dial func(addr string) *redis.Client) *readRouter {
+	r := &readRouter{discover: discover, dial: dial, nodes: make(map[string]*routedNode)}
+	r.stop, r.cancel = context.WithCancel(context.Background())
+	return r
+}
+
+// ReadClient implements graph.ReadRouter. A nil client selects the master.
+func (r *readRouter) ReadClient(ctx context.Context, key string) (redis.UniversalClient, error) {
+	r.mu.Lock()
+	if r.opts.Routing == ReadMaster || r.closed {
+		r.mu.Unlock()
+		return nil, nil
+	}
+	if r.refreshed.IsZero() {
+		r.mu.Unlock()
+		if err := r.refresh(ctx); err != nil {
+			return nil, err
+		}
+		r.mu.Lock()
+	} else if !r.refreshing && time.Since(r.refreshed) > r.opts.RefreshInterval {
+		r.refreshing = true
+		interval := r.opts.RefreshInterval
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			ctx, cancel := context.WithTimeout(r.stop, interval)
+			defer cancel()
+			_ = r.refresh(ctx)
+		}()
+	}
+	defer r.mu.Unlock()
+
+	slot := hashslot.Slot(key)
+	var shard *replicaShard
+	for i := range r.shards {
+		if r.shards[i].start <= slot && slot <= r.shards[i].end {
+			shard = &r.shards[i]
+			break
+		}
+	}
+	if shard == nil {
+		return nil, nil
+	}
+
+	var candidates []*routedNode
+	for _, addr := range shard.replicas {
+		if n := r.nodes[addr]; n != nil && n.healthy {
+			candidates = append(candidates, n)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	switch r.opts.Routing {
+	case ReadNearest:
+		best := r.nodes[shard.master]
+		for _, n := range candidates {
+			if best == nil || !best.healthy || n.latency < best.latency {
+				best = n
+			}
+		}
+		if best == r.nodes[shard.master] {
+			return nil, nil
+		}
+		return best.client, nil
+	case ReadRandom:
+		i := rand.Intn(len(candidates) + 1)
+		if i == len(candidates) {
+			return nil, nil
+		}
+		return candidates[i].client, nil
+	default:
+		return candidates[rand.Intn(len(candidates))].client, nil
+	}
+}
+
+// refresh rediscovers the topology and checks the health of every node.
+func (r *readRouter) refresh(ctx context.Context) error {
+	r.refreshMu.Lock()
+	defer r.refreshMu.Unlock()
+
+	shards, err := r.discover(ctx)
+	if err != nil {
+		r.mu.Lock()
+		r.refreshing = false
+		r.mu.Unlock()
+		return err
+	}
+
+	r.mu.Lock()
+	if r.closed {
+		r.refreshing = false
+		r.mu.Unlock()
+		return ErrClosed
+	}
+	maxLag := r.opts.MaxLagBytes
+	clients := make(map[string]*redis.Client)
+	masters := make(map[string]bool)
+	for _, s := range shards {
+		masters[s.master] = true
+		for _, addr := range append([]string{s.master}, s.replicas...) {
+			if n := r.nodes[addr]; n != nil {
+				clients[addr] = n.client
+			} else {
+				clients[addr] = r.dial(addr)
+			}
+		}
+	}
+	r.mu.Unlock()
+
+	nodes := make(map[string]*routedNode, len(clients))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for addr, client := range clients {
+		wg.Add(1)
+		go func(addr string, client *redis.Client) {
+			defer wg.Done()
+			n := &routedNode{client: client}
+			n.healthy, n.latency, n.offset = checkNode(ctx, client, !masters[addr], maxLag > 0)
+			mu.Lock()
+			nodes[addr] = n
+			mu.Unlock()
+		}(addr, client)
+	}
+	wg.Wait()
+	if maxLag > 0 {
+		excludeLagging(shards, nodes, maxLag)
+	}
+
+	r.mu.Lock()
+	if r.closed {
+		// close ran while the nodes were checked
+		for addr, n := range nodes {
+			if r.nodes[addr] == nil {
+				_ = n.client.Close()
+			}
+		}
+		r.refreshing = false
+		r.mu.Unlock()
+		return ErrClosed
+	}
+	for addr, n := range r.nodes {
+		if _, ok := nodes[addr]; !ok {
+			_ = n.client.Close()
+		}
+	}
+	r.shards = shards
+	r.nodes = nodes
+	r.refreshed = time.Now()
+	r.refreshing = false
+	r.mu.Unlock()
+	return nil
+}
+
+// invalidate makes the next read rediscover the topology.
+func (r *readRouter) invalidate() {
+	r.mu.Lock()
+	r.refreshed = time.Time{}
+	r.mu.Unlock()
+}
+
+// close stops the background refresh, waits for it and closes the clients
+// of all discovered nodes.
+func (r *readRouter) close() error {
+	r.mu.Lock()
+	r.closed = true
+	r.mu.Unlock()
+	r.cancel()
+	r.wg.Wait()
+	r.refreshMu.Lock()
+	defer r.refreshMu.Unlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var first error
+	for addr, n := range r.nodes {
+		if err := n.client.Close(); err != nil && first == nil {
+			first = err
+		}
+		delete(r.nodes, addr)
+	}
+	return first
+}
+
+// checkNode measures the latency of a node and, for a replica, whether it is
+// in sync with its master. With offsets it also returns the replication
+// offset of the node, or -1 when it is unknown.
+func checkNode(ctx context.Context, client *redis.Client, replica, offsets bool) (bool, time.Duration, int64) {
+	start := time.Now()
+	if err := client.Ping(ctx).Err(); err != nil {
+		return false, 0, -1
+	}
+	latency := time.Since(start)
+	if !replica && !offsets {
+		return true, latency, -1
+	}
+
+	info, err := client.Info(ctx, "replication").Result()
+	if err != nil {
+		return !replica, latency, -1
+	}
+	fields := parseInfo(info)
+	offsetField := "master_repl_offset"
+	if replica {
+		if fields["role"] != "slave" || fields["master_link_status"] != "up" || fields["master_sync_in_progress"] == "1" {
+			return false, latency, -1
+		}
+		offsetField = "slave_repl_offset"
+	}
+	offset, err := strconv.ParseInt(fields[offsetField], 10, 64)
+	if err != nil {
+		offset = -1
+	}
+	return true, latency, offset
+}
+
+// excludeLagging marks replicas more than maxLag bytes behind their master
+// as unhealthy. Replicas of a master whose offset is unknown are kept, since
+// the master cannot serve the reads either.
+func excludeLagging(shards []replicaShard, nodes map[string]*routedNode, maxLag int64) {
+	for _, s := range shards {
+		master := nodes[s.master]
+		if master == nil || master.offset < 0 {
+			continue
+		}
+		for _, addr := range s.replicas {
+			n := nodes[addr]
+			if n == nil || !n.healthy {
+				continue
+			}
+			if n.offset < 0 || master.offset-n.offset > maxLag {
+				n.healthy = false
+			}
+		}
+	}
+}
+
+// parseInfo parses the key:value lines of an INFO reply.
+func parseInfo(info string) map[string]string {
+	fields := make(map[string]string)
+	for _, line := range strings.Split(info, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		if k, v, ok := strings.Cut(line, ":"); ok {
+			fields[k] = v
+		}
+	}
+	return fields
+}
+
+func clusterTopology(cc *redis.ClusterClient) (func(ctx context.Context) ([]replicaShard, error), func(addr string) *redis.Client) {
+	var base *redis.Options
+	discover := func(ctx context.Context) ([]replicaShard, error) {
+		if base == nil {
+			master, err := cc.MasterForKey(ctx, "")
+			if err != nil {
+				return nil, err
+			}
+			base = master.Options()
+		}
+		slots, err := cc.ClusterSlots(ctx).Result()
+		if err != nil {
+			return nil, err
+		}
+		shards := make([]replicaShard, 0, len(slots))
+		for _, s := range slots {
+			if len(s.Nodes) == 0 {
+				continue
+			}
+			shard := replicaShard{start: s.Start, end: s.End, master: s.Nodes[0].Addr}
+			for _, n := range s.Nodes[1:] {
+				shard.replicas = append(shard.replicas, n.Addr)
+			}
+			shards = append(shards, shard)
+		}
+		return shards, nil
+	}
+	dial := func(addr string) *redis.Client {
+		opt := *base
+		opt.Addr = addr
+		onConnect := opt.OnConnect
+		opt.OnConnect = func(ctx context.Context, cn *redis.Conn) error {
+			if onConnect != nil {
+				if err := onConnect(ctx, cn); err != nil {
+					return err
+				}
+			}
+			return cn.ReadOnly(ctx).Err()
+		}
+		return redis.NewClient(&opt)
+	}
+	return discover, dial
+}
+
+func sentinelTopology(fo *redis.FailoverOptions) (func(ctx context.Context) ([]replicaShard, error), func(addr string) *redis.Client) {
+	discover := func(ctx context.Context) ([]replicaShard, error) {
+		var last error
+		for _, addr := range fo.SentinelAddrs {
+			shard, err := querySentinel(ctx, fo, addr)
+			if err == nil {
+				return []replicaShard{shard}, nil
+			}
+			last = err
+		}
+		if last == nil {
+			last = errors.New("no sentinel addresses")
+		}
+		return nil, last
+	}
+	dial := func(addr string) *redis.Client {
+		return redis.NewClient(&redis.Options{
+			Addr:            addr,
+			Dialer:          fo.Dialer,
+			Protocol:        fo.Protocol,
+			ClientName:      fo.ClientName,
+			Username:        fo.Username,
+			Password:        fo.Password,
+			DB:              fo.DB,
+			MaxRetries:      fo.MaxRetries,
+			MinRetryBackoff: fo.MinRetryBackoff,
+			MaxRetryBackoff: fo.MaxRetryBackoff,
+			DialTimeout:     fo.DialTimeout,
+			ReadTimeout:     fo.ReadTimeout,
+			WriteTimeout:    fo.WriteTimeout,
+			PoolFIFO:        fo.PoolFIFO,
+			PoolSize:        fo.PoolSize,
+			PoolTimeout:     fo.PoolTimeout,
+			MinIdleConns:    fo.MinIdleConns,
+			MaxIdleConns:    fo.MaxIdleConns,
+			TLSConfig:       fo.TLSConfig,
+		})
+	}
+	return discover, dial
+}
+
+// querySentinel asks one sentinel for the master and the usable replicas.
+func querySentinel(ctx context.Context, fo *redis.FailoverOptions, addr string) (replicaShard, error) {
+	sentinel := redis.NewSentinelClient(&redis.Options{
+		Addr:        addr,
+		Dialer:      fo.Dialer,
+		Username:    fo.SentinelUsername,
+		Password:    fo.SentinelPassword,
+		DialTimeout: fo.DialTimeout,
+		ReadTimeout: fo.ReadTimeout,
+		TLSConfig:   fo.TLSConfig,
+	})
+	defer sentinel.Close()
+
+	master, err := sentinel.GetMasterAddrByName(ctx, fo.MasterName).Result()
+	if err != nil {
+		return replicaShard{}, err
+	}
+	if len(master) != 2 {
+		return replicaShard{}, errors.New("malformed sentinel master address")
+	}
+	replicas, err := sentinel.Replicas(ctx, fo.MasterName).Result()
+	if err != nil {
+		return replicaShard{}, err
+	}
+
+	shard := replicaShard{start: 0, end: hashslot.Count - 1, master: net.JoinHostPort(master[0], master[1])}
+	for _, rep := range replicas {
+		if strings.Contains(rep["flags"], "s_down") || strings.Contains(rep["flags"], "o_down") || strings.Contains(rep["flags"], "disconnected") {
+			continue
+		}
+		shard.replicas = append(shard.replicas, net.JoinHostPort(rep["ip"], rep["port"]))
+	}
+	return shard, nil
+}
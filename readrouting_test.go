@@ -0,0 +1,88 @@
+package falkordb
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/snowmerak/falkordb-go/util/hashslot"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExcludeLagging(t *testing.T) {
+	nodes := map[string]*routedNode{
+		"m1":  {healthy: true, offset: 1000},
+		"r1a": {healthy: true, offset: 990},
+		"r1b": {healthy: true, offset: 500},
+		"r1c": {healthy: true, offset: -1},
+		"m2":  {healthy: false, offset: -1},
+		"r2":  {healthy: true, offset: 10},
+	}
+	shards := []replicaShard{
+		{master: "m1", replicas: []string{"r1a", "r1b", "r1c"}},
+		{master: "m2", replicas: []string{"r2"}},
+	}
+
+	excludeLagging(shards, nodes, 100)
+	assert.True(t, nodes["r1a"].healthy)
+	assert.False(t, nodes["r1b"].healthy)
+	assert.False(t, nodes["r1c"].healthy)
+	assert.True(t, nodes["r2"].healthy, "kept while the master offset is unknown")
+}
+
+func TestParseInfo(t *testing.T) {
+	fields := parseInfo("# Replication\r\nrole:slave\r\nslave_repl_offset:42\r\n")
+	assert.Equal(t, "slave", fields["role"])
+	assert.Equal(t, "42", fields["slave_repl_offset"])
+}
+
+func TestReadRouterCloseStopsRefresh(t *testing.T) {
+	started := make(chan struct{})
+	calls := 0
+	discover := func(ctx context.Context) ([]replicaShard, error) {
+		calls++
+		if calls == 1 {
+			return []replicaShard{{start: 0, end: hashslot.Count - 1, master: "m", replicas: []string{"r1"}}}, nil
+		}
+		close(started)
+		<-ctx.Done()
+		return []replicaShard{{start: 0, end: hashslot.Count - 1, master: "m", replicas: []string{"r2"}}}, nil
+	}
+	var dialed []string
+	dial := func(addr string) *redis.Client {
+		dialed = append(dialed, addr)
+		return redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1, DialTimeout: 10 * time.Millisecond})
+	}
+	r := newTopologyRouter(discover, dial)
+	r.opts = ReadRoutingOptions{Routing: ReadReplica, RefreshInterval: time.Hour}
+
+	_, err := r.ReadClient(context.Background(), "g")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"m", "r1"}, sortedStrings(dialed))
+
+	// a stale topology starts a background refresh, which close cancels and
+	// waits for before closing the clients
+	r.mu.Lock()
+	r.refreshed = time.Now().Add(-2 * time.Hour)
+	r.mu.Unlock()
+	_, err = r.ReadClient(context.Background(), "g")
+	require.NoError(t, err)
+	<-started
+
+	require.NoError(t, r.close())
+	assert.Equal(t, []string{"m", "r1"}, sortedStrings(dialed), "no client is dialed once closed")
+	assert.Empty(t, r.nodes)
+
+	c, err := r.ReadClient(context.Background(), "g")
+	assert.NoError(t, err)
+	assert.Nil(t, c)
+}
+
+func sortedStrings(s []string) []string {
+	s = append([]string(nil), s...)
+	sort.Strings(s)
+	return s
+}
@@ -0,0 +1,100 @@
+package falkordb
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/snowmerak/falkordb-go/graph"
+)
+
+// RestoreCypher replays a script written by Graph.DumpCypher into g, one
+// batched statement at a time, and returns the summed statistics. Lines
+// starting with "//" are comments, except constraint directives, which are
+// sent as GRAPH.CONSTRAINT commands on g.
+func RestoreCypher(ctx context.Context, g *graph.Graph, r io.Reader) (graph.QueryStatistics, error) {
+	var stats graph.QueryStatistics
+	if g.ReadOnly() {
+		return stats, graph.ErrReadOnly
+	}
+	rd := bufio.NewReaderSize(r, 1<<20)
+
+	for line := 1; ; line++ {
+		text, readErr := rd.ReadString('\n')
+		if readErr != nil && readErr != io.EOF {
+			return stats, readErr
+		}
+
+		stmt := strings.TrimSpace(text)
+		switch {
+		case strings.HasPrefix(stmt, graph.ConstraintDirective):
+			var args []string
+			if err := json.Unmarshal([]byte(stmt[len(graph.ConstraintDirective):]), &args); err != nil || len(args) == 0 {
+				return stats, fmt.Errorf("line %d: invalid constraint directive", line)
+			}
+			cmd := []interface{}{"GRAPH.CONSTRAINT", args[0], g.Id}
+			for _, a := range args[1:] {
+				cmd = append(cmd, a)
+			}
+			if err := g.Conn.Do(ctx, cmd...).Err(); err != nil {
+				return stats, fmt.Errorf("line %d: %w", line, err)
+			}
+		case stmt == "" || strings.HasPrefix(stmt, "//"):
+		default:
+			qr, err := g.QueryContext(ctx, strings.TrimSuffix(stmt, ";"), nil, nil)
+			if err != nil {
+				return stats, fmt.Errorf("line %d: %w", line, err)
+			}
+			stats.Add(qr.Stats())
+		}
+
+		if readErr == io.EOF {
+			return stats, nil
+		}
+	}
+}
+
+// RestoreGraph restores a snapshot taken with Graph.Snapshot under name using
+// RESTORE. An existing graph is overwritten only when replace is set.
+// Snapshots cannot be restored on an older module version than they were
+// taken from.
+func (db *FalkorDB) RestoreGraph(ctx context.Context, name string, blob []byte, replace bool) error {
+	info, payload, err := graph.ReadSnapshot(bytes.NewReader(blob))
+	if err != nil {
+		return err
+	}
+	return db.restoreGraph(ctx, name, info, payload, replace)
+}
+
+// RestoreGraphFrom restores a snapshot written by Graph.SnapshotTo.
+func (db *FalkorDB) RestoreGraphFrom(ctx context.Context, name string, r io.Reader, replace bool) error {
+	info, payload, err := graph.ReadSnapshot(r)
+	if err != nil {
+		return err
+	}
+	return db.restoreGraph(ctx, name, info, payload, replace)
+}
+
+func (db *FalkorDB) restoreGraph(ctx context.Context, name string, info graph.SnapshotInfo, payload []byte, replace bool) error {
+	if db.readOnly() {
+		return graph.ErrReadOnly
+	}
+
+	g := db.SelectGraph(name)
+	version, err := g.ModuleVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if version < info.ModuleVersion {
+		return fmt.Errorf("snapshot of module version %d cannot be restored on module version %d", info.ModuleVersion, version)
+	}
+
+	if replace {
+		return db.Conn.RestoreReplace(ctx, name, 0, string(payload)).Err()
+	}
+	return db.Conn.Restore(ctx, name, 0, string(payload)).Err()
+}
@@ -0,0 +1,276 @@
+package falkordb
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const defaultSentinelPort = "26379"
+
+// SentinelOption configures a connection through Redis Sentinel.
+type SentinelOption = redis.FailoverOptions
+
+// FailoverEvent describes a master switch announced by sentinel.
+type FailoverEvent struct {
+	MasterName string
+	OldAddr    string
+	NewAddr    string
+}
+
+// NewSentinel creates a new FalkorDB instance that finds the master of
+// options.MasterName through the sentinels in options.SentinelAddrs and
+// follows it across failovers. When RouteByLatency or RouteRandomly is set,
+// read only queries are routed to replicas as with SetReadRouting using
+// ReadNearest or ReadRandom.
+func NewSentinel(options *SentinelOption) (*FalkorDB, error) {
+	return newSentinel(options, false)
+}
+
+// NewSentinelReadOnly creates a new read-only FalkorDB instance through Redis Sentinel.
+func NewSentinelReadOnly(options *SentinelOption) (*FalkorDB, error) {
+	return newSentinel(options, true)
+}
+
+func newSentinel(options *SentinelOption, isReadonly bool) (*FalkorDB, error) {
+	if options.MasterName == "" {
+		return nil, errors.New("sentinel master name is required")
+	}
+	if len(options.SentinelAddrs) == 0 {
+		return nil, errors.New("at least one sentinel address is required")
+	}
+
+	failover := *options
+	failover.SentinelAddrs = append([]string(nil), options.SentinelAddrs...)
+	db := &FalkorDB{
+		Conn:     redis.NewFailoverClient(&failover),
+		readonly: isReadonly,
+		failover: &failover,
+	}
+
+	routing := ReadMaster
+	switch {
+	case failover.RouteByLatency:
+		routing = ReadNearest
+	case failover.RouteRandomly:
+		routing = ReadRandom
+	}
+	if routing != ReadMaster {
+		if err := db.SetReadRouting(&ReadRoutingOptions{Routing: routing}); err != nil {
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
+// failoverOptions builds sentinel options for masterName from the options of
+// a connection to a sentinel.
+func failoverOptions(options *ConnectionOption, masterName string) *SentinelOption {
+	return &SentinelOption{
+		MasterName:                 masterName,
+		SentinelAddrs:              []string{options.Addr},
+		ClientName:                 options.ClientName,
+		SentinelUsername:           options.Username,
+		SentinelPassword:           options.Password,
+		Dialer:                     options.Dialer,
+		OnConnect:                  options.OnConnect,
+		Protocol:                   options.Protocol,
+		Username:                   options.Username,
+		Password:                   options.Password,
+		CredentialsProvider:        options.CredentialsProvider,
+		CredentialsProviderContext: options.CredentialsProviderContext,
+		DB:                         options.DB,
+		MaxRetries:                 options.MaxRetries,
+		MinRetryBackoff:            options.MinRetryBackoff,
+		MaxRetryBackoff:            options.MaxRetryBackoff,
+		DialTimeout:                options.DialTimeout,
+		ReadTimeout:                options.ReadTimeout,
+		WriteTimeout:               options.WriteTimeout,
+		ContextTimeoutEnabled:      options.ContextTimeoutEnabled,
+		ReadBufferSize:             options.ReadBufferSize,
+		WriteBufferSize:            options.WriteBufferSize,
+		PoolFIFO:                   options.PoolFIFO,
+		PoolSize:                   options.PoolSize,
+		PoolTimeout:                options.PoolTimeout,
+		MinIdleConns:               options.MinIdleConns,
+		MaxIdleConns:               options.MaxIdleConns,
+		MaxActiveConns:             options.MaxActiveConns,
+		ConnMaxIdleTime:            options.ConnMaxIdleTime,
+		ConnMaxLifetime:            options.ConnMaxLifetime,
+		TLSConfig:                  options.TLSConfig,
+		DisableIdentity:            options.DisableIdentity,
+		IdentitySuffix:             options.IdentitySuffix,
+		UnstableResp3:              options.UnstableResp3,
+	}
+}
+
+// ParseSentinelURL parses a sentinel URL of the form
+//
+//	falkor+sentinel://[user:password@]host1[:port],host2[:port]/master-name[/db][?option=value]
+//
+// falkors+sentinel:// enables TLS. Ports default to 26379. The credentials
+// are used for both the sentinels and the data nodes unless the username and
+// password query options set the latter. The other query options are those
+// accepted by redis.ParseFailoverURL.
+func ParseSentinelURL(rawURL string) (*SentinelOption, error) {
+	var scheme string
+	switch {
+	case strings.HasPrefix(rawURL, "falkor+sentinel://"):
+		scheme = "redis"
+	case strings.HasPrefix(rawURL, "falkors+sentinel://"):
+		scheme = "rediss"
+	default:
+		return nil, errors.New("sentinel URL must start with falkor+sentinel:// or falkors+sentinel://")
+	}
+	rest := rawURL[strings.Index(rawURL, "://")+3:]
+
+	var userinfo string
+	if at := strings.LastIndex(strings.SplitN(rest, "/", 2)[0], "@"); at >= 0 {
+		userinfo, rest = rest[:at+1], rest[at+1:]
+	}
+	hosts, path, _ := strings.Cut(rest, "/")
+	path, query, _ := strings.Cut(path, "?")
+	if hosts == "" {
+		return nil, errors.New("sentinel URL has no sentinel address")
+	}
+	masterName, db, _ := strings.Cut(path, "/")
+	if masterName == "" {
+		return nil, errors.New("sentinel URL has no master name")
+	}
+	masterName, err := url.PathUnescape(masterName)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs := strings.Split(hosts, ",")
+	for i, addr := range addrs {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			addrs[i] = net.JoinHostPort(strings.Trim(addr, "[]"), defaultSentinelPort)
+		}
+	}
+
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	values.Set("master_name", masterName)
+	for _, addr := range addrs[1:] {
+		values.Add("addr", addr)
+	}
+	u := scheme + "://" + userinfo + addrs[0]
+	if db != "" {
+		u += "/" + db
+	}
+	options, err := redis.ParseFailoverURL(u + "?" + values.Encode())
+	if err != nil {
+		return nil, err
+	}
+	if options.Username == "" && options.Password == "" {
+		options.Username = options.SentinelUsername
+		options.Password = options.SentinelPassword
+	}
+	return options, nil
+}
+
+// FromSentinelURL creates a new FalkorDB instance from a sentinel URL, see
+// ParseSentinelURL.
+func FromSentinelURL(rawURL string) (*FalkorDB, error) {
+	options, err := ParseSentinelURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return NewSentinel(options)
+}
+
+// failoverWatcher delivers +switch-master events of the sentinels.
+type failoverWatcher struct {
+	mu       sync.Mutex
+	handlers []func(FailoverEvent)
+	cancel   context.CancelFunc
+}
+
+// OnFailover registers fn to be called from a background goroutine whenever
+// a sentinel announces that the master moved. The first registration starts
+// a subscription to the sentinels, which moves to the next sentinel when the
+// current one is unreachable. It is only available on sentinel connections.
+func (db *FalkorDB) OnFailover(fn func(FailoverEvent)) error {
+	if db.failover == nil {
+		return errors.New("failover events require a sentinel connection")
+	}
+	if db.life.isClosed() {
+		return ErrClosed
+	}
+
+	db.watcherOnce.Do(func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		db.watcher = &failoverWatcher{cancel: cancel}
+		go db.watchFailovers(ctx)
+	})
+
+	db.watcher.mu.Lock()
+	db.watcher.handlers = append(db.watcher.handlers, fn)
+	db.watcher.mu.Unlock()
+	return nil
+}
+
+func (db *FalkorDB) watchFailovers(ctx context.Context) {
+	fo := db.failover
+	for i := 0; ctx.Err() == nil; i++ {
+		sentinel := redis.NewSentinelClient(&redis.Options{
+			Addr:        fo.SentinelAddrs[i%len(fo.SentinelAddrs)],
+			Dialer:      fo.Dialer,
+			Username:    fo.SentinelUsername,
+			Password:    fo.SentinelPassword,
+			DialTimeout: fo.DialTimeout,
+			TLSConfig:   fo.TLSConfig,
+		})
+		pubsub := sentinel.Subscribe(ctx, "+switch-master")
+		for {
+			msg, err := pubsub.ReceiveMessage(ctx)
+			if err != nil {
+				break
+			}
+			if ev, ok := parseSwitchMaster(msg.Payload); ok && ev.MasterName == fo.MasterName {
+				db.failedOver(ev)
+			}
+		}
+		_ = pubsub.Close()
+		_ = sentinel.Close()
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func (db *FalkorDB) failedOver(ev FailoverEvent) {
+	if db.reads != nil {
+		db.reads.invalidate()
+	}
+	db.watcher.mu.Lock()
+	handlers := append([](func(FailoverEvent))(nil), db.watcher.handlers...)
+	db.watcher.mu.Unlock()
+	for _, fn := range handlers {
+		fn(ev)
+	}
+}
+
+// parseSwitchMaster parses "<name> <old-ip> <old-port> <new-ip> <new-port>".
+func parseSwitchMaster(payload string) (FailoverEvent, bool) {
+	parts := strings.Fields(payload)
+	if len(parts) != 5 {
+		return FailoverEvent{}, false
+	}
+	return FailoverEvent{
+		MasterName: parts[0],
+		OldAddr:    net.JoinHostPort(parts[1], parts[2]),
+		NewAddr:    net.JoinHostPort(parts[3], parts[4]),
+	}, true
+}
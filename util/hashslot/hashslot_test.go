@@ -0,0 +1,21 @@
+package hashslot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCRC16(t *testing.T) {
+	assert.Equal(t, uint16(0x31C3), crc16("123456789"))
+}
+
+func TestSlot(t *testing.T) {
+	assert.Equal(t, 12739, Slot("123456789"))
+	assert.Equal(t, 0, Slot(""))
+	assert.Equal(t, Slot("user1000"), Slot("{user1000}.following"))
+	assert.Equal(t, Slot("{user1000}.following"), Slot("{user1000}.followers"))
+	assert.Equal(t, int(crc16("foo{}{bar}"))%Count, Slot("foo{}{bar}"))
+	assert.Equal(t, int(crc16("{bar"))%Count, Slot("{bar"))
+	assert.Equal(t, Slot("zap"), Slot("foo{zap}bar{baz}"))
+}
@@ -1,60 +1,187 @@
 package strs
 
 import (
+	"bytes"
 	"crypto/rand"
+	"fmt"
+	"math"
 	"strconv"
 	"strings"
 )
 
-func arrayToString(arr []interface{}) string {
-	strArray := make([]string, 0, len(arr))
+// CypherValue is implemented by values that encode themselves as a Cypher
+// expression, such as the temporal types of the domain package.
+type CypherValue interface {
+	CypherLiteral() string
+}
+
+// CypherAppender is implemented by values that can append their Cypher
+// encoding to a buffer without an intermediate string, such as large vectors.
+// AppendCypher returns an error if the value has no Cypher encoding.
+type CypherAppender interface {
+	AppendCypher(dst []byte) ([]byte, error)
+}
+
+func appendArray(dst []byte, arr []interface{}) ([]byte, error) {
+	dst = append(dst, '[')
+	for i := 0; i < len(arr); i++ {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		var err error
+		if dst, err = AppendValue(dst, arr[i]); err != nil {
+			return nil, err
+		}
+	}
+	return append(dst, ']'), nil
+}
+
+func appendStrArray(dst []byte, arr []string) []byte {
+	dst = append(dst, '[')
 	for i := 0; i < len(arr); i++ {
-		strArray = append(strArray, ToString(arr[i]))
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = strconv.AppendQuote(dst, arr[i])
 	}
-	return "[" + strings.Join(strArray, ",") + "]"
+	return append(dst, ']')
 }
 
-func strArrayToString(arr []string) string {
-	strArray := make([]string, 0, len(arr))
+func appendFloat32Array(dst []byte, arr []float32) ([]byte, error) {
+	dst = append(dst, '[')
 	for i := 0; i < len(arr); i++ {
-		strArray = append(strArray, ToString(arr[i]))
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		if err := CheckFloat(float64(arr[i])); err != nil {
+			return nil, err
+		}
+		dst = AppendFloat(dst, float64(arr[i]), 32)
+	}
+	return append(dst, ']'), nil
+}
+
+// CheckFloat returns an error if f is NaN or infinite, which Cypher has no
+// literal for.
+func CheckFloat(f float64) error {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Errorf("float %v has no Cypher literal", f)
+	}
+	return nil
+}
+
+// AppendFloat appends the shortest representation of f that parses back to
+// the same value at bitSize. Exponents are written without a plus sign, as
+// in 1e20 or 1e-07, since Cypher does not accept one.
+func AppendFloat(dst []byte, f float64, bitSize int) []byte {
+	start := len(dst)
+	dst = strconv.AppendFloat(dst, f, 'g', -1, bitSize)
+	if i := bytes.IndexByte(dst[start:], '+'); i > 0 {
+		i += start
+		dst = append(dst[:i], dst[i+1:]...)
 	}
-	return "[" + strings.Join(strArray, ",") + "]"
+	return dst
 }
 
-func mapToString(data map[string]interface{}) string {
-	pairsArray := make([]string, 0, len(data))
+func appendMap(dst []byte, data map[string]interface{}) ([]byte, error) {
+	dst = append(dst, '{')
+	first := true
 	for k, v := range data {
-		pairsArray = append(pairsArray, k+": "+ToString(v))
+		if !first {
+			dst = append(dst, ',')
+		}
+		first = false
+		dst = appendKey(dst, k)
+		dst = append(dst, ": "...)
+		var err error
+		if dst, err = AppendValue(dst, v); err != nil {
+			return nil, err
+		}
 	}
-	return "{" + strings.Join(pairsArray, ",") + "}"
+	return append(dst, '}'), nil
+}
+
+// appendKey appends a map key, quoted in backticks unless it is a plain
+// identifier, so that keys such as "first name" or "a-b" stay a single key.
+func appendKey(dst []byte, k string) []byte {
+	if isIdentifier(k) {
+		return append(dst, k...)
+	}
+	dst = append(dst, '`')
+	dst = append(dst, strings.ReplaceAll(k, "`", "``")...)
+	return append(dst, '`')
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || i > 0 && '0' <= c && c <= '9' {
+			continue
+		}
+		return false
+	}
+	return true
 }
 
 // ToString converts supported Go values to Cypher-friendly strings.
 func ToString(i interface{}) string {
+	return string(Append(nil, i))
+}
+
+// Append appends the Cypher encoding of a supported Go value to dst. It
+// panics on unsupported types; see AppendValue.
+func Append(dst []byte, i interface{}) []byte {
+	dst, err := AppendValue(dst, i)
+	if err != nil {
+		panic("Unrecognized type to convert to string")
+	}
+	return dst
+}
+
+// AppendValue appends the Cypher encoding of a supported Go value to dst, or
+// returns an error if i or a value nested in it has an unsupported type or
+// is a NaN or infinite float.
+func AppendValue(dst []byte, i interface{}) ([]byte, error) {
 	if i == nil {
-		return "null"
+		return append(dst, "null"...), nil
 	}
 
 	switch v := i.(type) {
+	case CypherAppender:
+		return v.AppendCypher(dst)
+	case CypherValue:
+		return append(dst, v.CypherLiteral()...), nil
 	case string:
-		return strconv.Quote(v)
+		return strconv.AppendQuote(dst, v), nil
 	case int:
-		return strconv.Itoa(v)
+		return strconv.AppendInt(dst, int64(v), 10), nil
 	case int64:
-		return strconv.FormatInt(v, 10)
+		return strconv.AppendInt(dst, v, 10), nil
 	case float64:
-		return strconv.FormatFloat(v, 'f', -1, 64)
+		if err := CheckFloat(v); err != nil {
+			return nil, err
+		}
+		return strconv.AppendFloat(dst, v, 'f', -1, 64), nil
+	case float32:
+		if err := CheckFloat(float64(v)); err != nil {
+			return nil, err
+		}
+		return AppendFloat(dst, float64(v), 32), nil
 	case bool:
-		return strconv.FormatBool(v)
+		return strconv.AppendBool(dst, v), nil
 	case []interface{}:
-		return arrayToString(v)
+		return appendArray(dst, v)
 	case map[string]interface{}:
-		return mapToString(v)
+		return appendMap(dst, v)
 	case []string:
-		return strArrayToString(v)
+		return appendStrArray(dst, v), nil
+	case []float32:
+		return appendFloat32Array(dst, v)
 	default:
-		panic("Unrecognized type to convert to string")
+		return nil, fmt.Errorf("unsupported value type %T", i)
 	}
 }
 
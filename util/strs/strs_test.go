@@ -1,6 +1,7 @@
 package strs
 
 import (
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -30,4 +31,74 @@ func TestUtils(t *testing.T) {
 	jsonMap["object"] = map[string]interface{}{"foo": 1}
 	res = ToString(jsonMap)
 	assert.Equal(t, res, "{object: {foo: 1}}")
+
+	res = ToString(literal("date('2023-01-01')"))
+	assert.Equal(t, res, "date('2023-01-01')")
+}
+
+type literal string
+
+func (l literal) CypherLiteral() string { return string(l) }
+
+func TestAppendFloat(t *testing.T) {
+	assert.Equal(t, "1e06", string(AppendFloat(nil, 1e6, 32)))
+	assert.Equal(t, "3.4028235e38", string(AppendFloat(nil, 3.4028235e38, 32)))
+	assert.Equal(t, "1e21", string(AppendFloat(nil, 1e21, 64)))
+	assert.Equal(t, "-2.5e-10", string(AppendFloat(nil, -2.5e-10, 64)))
+	assert.Equal(t, "1e-07", string(AppendFloat(nil, 1e-7, 32)))
+	assert.Equal(t, "0.25", string(AppendFloat(nil, 0.25, 64)))
+	assert.Equal(t, "x=1e06", string(AppendFloat([]byte("x="), 1e6, 64)))
+
+	assert.Equal(t, "[1e06,1e-07]", ToString([]float32{1e6, 1e-7}))
+	assert.Equal(t, "3e38", ToString(float32(3e38)))
+}
+
+func TestAppendValueUnsupported(t *testing.T) {
+	_, err := AppendValue(nil, []interface{}{1, map[string]interface{}{"x": struct{}{}}})
+	assert.EqualError(t, err, "unsupported value type struct {}")
+	assert.Panics(t, func() { ToString(struct{}{}) })
+
+	out, err := AppendValue([]byte("x="), []interface{}{int64(1), "a"})
+	assert.NoError(t, err)
+	assert.Equal(t, `x=[1,"a"]`, string(out))
+}
+
+func TestAppendValueQuotesMapKeys(t *testing.T) {
+	for key, want := range map[string]string{
+		"name":                   "{name: 1}",
+		"_v2":                    "{_v2: 1}",
+		"first name":             "{`first name`: 1}",
+		"a-b":                    "{`a-b`: 1}",
+		"2x":                     "{`2x`: 1}",
+		"a`b":                    "{`a``b`: 1}",
+		"x}) DETACH DELETE n //": "{`x}) DETACH DELETE n //`: 1}",
+		"":                       "{``: 1}",
+	} {
+		got, err := AppendValue(nil, map[string]interface{}{key: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, want, string(got), key)
+	}
+}
+
+func TestAppendValueRejectsNonFiniteFloats(t *testing.T) {
+	for _, v := range []interface{}{
+		math.NaN(),
+		math.Inf(1),
+		float32(math.Inf(-1)),
+		[]float32{1, float32(math.NaN())},
+		[]interface{}{1, math.Inf(-1)},
+		map[string]interface{}{"x": math.NaN()},
+		vector{1, float32(math.Inf(1))},
+	} {
+		_, err := AppendValue(nil, v)
+		assert.Error(t, err, "%v", v)
+		assert.Panics(t, func() { ToString(v) })
+	}
+
+	_, err := AppendValue(nil, math.Inf(1))
+	assert.EqualError(t, err, "float +Inf has no Cypher literal")
 }
+
+type vector []float32
+
+func (v vector) AppendCypher(dst []byte) ([]byte, error) { return appendFloat32Array(dst, v) }